@@ -38,6 +38,8 @@ type deleteCmd struct {
 	dryRun       bool
 	disableHooks bool
 	purge        bool
+	description  string
+	labels       []string
 
 	out    io.Writer
 	client helm.Interface
@@ -75,16 +77,24 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&del.dryRun, "dry-run", false, "simulate a delete")
 	f.BoolVar(&del.disableHooks, "no-hooks", false, "prevent hooks from running during deletion")
 	f.BoolVar(&del.purge, "purge", false, "remove the release from the store and make its name free for later use")
+	f.StringVar(&del.description, "description", "", "a description of why the release is being deleted")
+	f.StringSliceVar(&del.labels, "label", []string{}, "labels to add to the release. Separate labels with commas: key1=val1,key2=val2")
 
 	return cmd
 }
 
 func (d *deleteCmd) run() error {
+	labels, err := parseLabels(d.labels)
+	if err != nil {
+		return err
+	}
 	opts := []helm.DeleteOption{
 		helm.DeleteDryRun(d.dryRun),
 		helm.DeleteDisableHooks(d.disableHooks),
 		helm.DeletePurge(d.purge),
+		helm.DeleteDescription(d.description),
+		helm.DeleteLabels(labels),
 	}
-	_, err := d.client.DeleteRelease(d.name, opts...)
+	_, err = d.client.DeleteRelease(d.name, opts...)
 	return prettyError(err)
 }
@@ -67,7 +67,7 @@ func newGetCmd(client helm.Interface, out io.Writer) *cobra.Command {
 			}
 			get.release = args[0]
 			if get.client == nil {
-				get.client = helm.NewClient(helm.Host(tillerHost))
+				get.client = newClient()
 			}
 			return get.run()
 		},
@@ -134,5 +134,5 @@ func ensureHelmClient(h helm.Interface) helm.Interface {
 	if h != nil {
 		return h
 	}
-	return helm.NewClient(helm.Host(tillerHost))
+	return newClient()
 }
@@ -28,6 +28,10 @@ import (
 
 var getValuesHelp = `
 This command downloads a values file for a given release.
+
+By default it shows the values supplied when the release was installed or
+upgraded, with all of the '--values' files and '--set' values merged. Use
+'--all' to include the chart's default values.
 `
 
 type getValuesCmd struct {
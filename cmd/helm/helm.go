@@ -25,17 +25,21 @@ import (
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 )
 
 const (
-	homeEnvVar      = "HELM_HOME"
-	hostEnvVar      = "HELM_HOST"
-	tillerNamespace = "kube-system"
+	homeEnvVar            = "HELM_HOME"
+	hostEnvVar            = "HELM_HOST"
+	tillerNamespaceEnvVar = "TILLER_NAMESPACE"
 )
 
 var (
-	helmHome   string
-	tillerHost string
+	helmHome        string
+	tillerHost      string
+	tillerNamespace string
 )
 
 // flagDebug is a signal that the user wants additional output.
@@ -59,7 +63,8 @@ Common actions from this point include:
 
 Environment:
   $HELM_HOME      Set an alternative location for Helm files. By default, these are stored in ~/.helm
-  $HELM_HOST      Set an alternative Tiller host. The format is host:port.
+  $HELM_HOST      Set an alternative Tiller host. The format is host:port, or "local" to run without Tiller.
+  $TILLER_NAMESPACE Set an alternative Tiller namespace (default "kube-system").
   $KUBECONFIG     Set an alternate Kubernetes configuration file (default: "~/.kube/config").
 `
 
@@ -78,10 +83,18 @@ func newRootCmd(out io.Writer) *cobra.Command {
 		home = "$HOME/.helm"
 	}
 	thost := os.Getenv(hostEnvVar)
+	tns := os.Getenv(tillerNamespaceEnvVar)
+	if tns == "" {
+		tns = "kube-system"
+	}
 	p := cmd.PersistentFlags()
 	p.StringVar(&helmHome, "home", home, "location of your Helm config. Overrides $HELM_HOME.")
 	p.StringVar(&tillerHost, "host", thost, "address of tiller. Overrides $HELM_HOST.")
+	p.StringVar(&tillerNamespace, "tiller-namespace", tns, "namespace of tiller. Overrides $TILLER_NAMESPACE.")
 	p.BoolVarP(&flagDebug, "debug", "", false, "enable verbose output")
+	p.BoolVar(&flagLocal, "local", false, "manage releases in-process with the current kubeconfig, without Tiller. Same as --host=local")
+	p.StringVar(&localStorage, "local-storage", localStorage, "the storage driver used in local mode. One of 'configmap' or 'memory'")
+	addTLSFlags(p)
 
 	cmd.AddCommand(
 		newCreateCmd(out),
@@ -102,6 +115,7 @@ func newRootCmd(out io.Writer) *cobra.Command {
 		newRepoCmd(out),
 		newDependencyCmd(out),
 		newSearchCmd(out),
+		newTemplateCmd(out),
 	)
 	return cmd
 }
@@ -117,6 +131,13 @@ func main() {
 }
 
 func setupConnection(c *cobra.Command, args []string) error {
+	if isLocal() {
+		if flagDebug {
+			fmt.Printf("Running locally with %s storage in %q\n", localStorage, tillerNamespace)
+		}
+		return setupLocal()
+	}
+
 	if tillerHost == "" {
 		tunnel, err := newTillerPortForwarder(tillerNamespace)
 		if err != nil {
@@ -133,7 +154,7 @@ func setupConnection(c *cobra.Command, args []string) error {
 	if flagDebug {
 		fmt.Printf("Server: %q\n", tillerHost)
 	}
-	return nil
+	return setupTLS()
 }
 
 func teardown() {
@@ -154,6 +175,41 @@ func checkArgsLength(argsReceived int, requiredArgs ...string) error {
 	return nil
 }
 
+// newClient creates a Tiller client that reports the current kubeconfig user
+// as part of the caller's identity, and connects over TLS if it is enabled.
+// In local mode, the client calls the in-process release server instead.
+func newClient() helm.Interface {
+	user, err := kube.CurrentUser()
+	if err != nil {
+		debug("Cannot determine kubeconfig user: %s", err)
+	}
+	if isLocal() {
+		return helm.NewLocalClient(localServer, helm.KubeUser(user))
+	}
+	return helm.NewClient(helm.Host(tillerHost), helm.KubeUser(user), helm.WithTLS(tlsConfig))
+}
+
+// debug prints a message if --debug is set.
+func debug(format string, args ...interface{}) {
+	if flagDebug {
+		fmt.Printf(format+"\n", args...)
+	}
+}
+
+// parseLabels parses a list of key=value pairs into a map.
+func parseLabels(pairs []string) (map[string]string, error) {
+	labels := map[string]string{}
+	for _, kv := range pairs {
+		parts := strings.SplitN(kv, "=", 2)
+		key := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || key == "" {
+			return nil, fmt.Errorf("invalid label %q: expected key=value", kv)
+		}
+		labels[key] = strings.TrimSpace(parts[1])
+	}
+	return labels, nil
+}
+
 // requireInit is a PreRunE implementation for validating that $HELM_HOME is configured.
 func requireInit(cmd *cobra.Command, args []string) error {
 	err := requireHome()
@@ -31,6 +31,21 @@ import (
 const initDesc = `
 This command installs Tiller (the helm server side component) onto your
 Kubernetes Cluster and sets up local configuration in $HELM_HOME (default: ~/.helm/)
+
+To secure the connection to Tiller with mutual TLS, pass the certificates to be
+mounted into Tiller:
+
+	$ helm init --tiller-tls-verify --tiller-tls-cert tiller.crt \
+		--tiller-tls-key tiller.key --tiller-tls-ca-cert ca.crt
+
+Then connect with 'helm --tls-verify', which reads ca.pem, cert.pem and key.pem
+from $HELM_HOME by default.
+
+Tiller can be installed into any namespace with '--tiller-namespace', and
+restricted to installing releases into a set of namespaces. This lets each team
+run its own Tiller:
+
+	$ helm init --tiller-namespace team-a --tiller-allowed-namespaces team-a,team-a-staging
 `
 
 var (
@@ -41,7 +56,14 @@ var (
 type initCmd struct {
 	image      string
 	clientOnly bool
+	namespaces []string
 	out        io.Writer
+
+	tlsEnable     bool
+	tlsVerify     bool
+	tlsCertFile   string
+	tlsKeyFile    string
+	tlsCaCertFile string
 }
 
 func newInitCmd(out io.Writer) *cobra.Command {
@@ -61,6 +83,12 @@ func newInitCmd(out io.Writer) *cobra.Command {
 	}
 	cmd.Flags().StringVarP(&i.image, "tiller-image", "i", "", "override tiller image")
 	cmd.Flags().BoolVarP(&i.clientOnly, "client-only", "c", false, "If set does not install tiller")
+	cmd.Flags().StringSliceVar(&i.namespaces, "tiller-allowed-namespaces", []string{}, "namespaces tiller may install releases into. If empty, all namespaces are allowed")
+	cmd.Flags().BoolVar(&i.tlsEnable, "tiller-tls", false, "install tiller with TLS enabled")
+	cmd.Flags().BoolVar(&i.tlsVerify, "tiller-tls-verify", false, "install tiller with TLS enabled and client certificates verified. Implies --tiller-tls")
+	cmd.Flags().StringVar(&i.tlsCertFile, "tiller-tls-cert", "", "path to the TLS certificate file to install with tiller")
+	cmd.Flags().StringVar(&i.tlsKeyFile, "tiller-tls-key", "", "path to the TLS key file to install with tiller")
+	cmd.Flags().StringVar(&i.tlsCaCertFile, "tiller-tls-ca-cert", "", "path to the CA certificate tiller uses to verify clients")
 	return cmd
 }
 
@@ -71,7 +99,17 @@ func (i *initCmd) run() error {
 	}
 
 	if !i.clientOnly {
-		if err := installer.Install(tillerNamespace, i.image, flagDebug); err != nil {
+		opts := &installer.Options{
+			Namespace:         tillerNamespace,
+			Image:             i.image,
+			AllowedNamespaces: i.namespaces,
+			EnableTLS:         i.tlsEnable,
+			VerifyTLS:         i.tlsVerify,
+			TLSCertFile:       i.tlsCertFile,
+			TLSKeyFile:        i.tlsKeyFile,
+			TLSCaCertFile:     i.tlsCaCertFile,
+		}
+		if err := installer.Install(opts); err != nil {
 			if !strings.Contains(err.Error(), `"tiller-deploy" already exists`) {
 				return fmt.Errorf("error installing: %s", err)
 			}
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -29,7 +28,6 @@ import (
 	"text/template"
 
 	"github.com/Masterminds/sprig"
-	"github.com/ghodss/yaml"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/cmd/helm/downloader"
@@ -54,18 +52,29 @@ or
 
 	$ helm install --set name=prod redis
 
+The '--values' flag may be repeated, and may name a URL. The files are merged
+in order, each overriding the ones before it, and '--set' overrides them all:
+
+	$ helm install -f base.yaml -f prod.yaml --set replicas=3 redis
+
 To check the generated manifests of a release without installing the chart,
 the '--debug' and '--dry-run' flags can be combined. This will still require a
-round-trip to the Tiller server.
+round-trip to the Tiller server. To render the templates locally instead, use
+'helm template'.
 
 If --verify is set, the chart MUST have a provenance file, and the provenenace
 fall MUST pass all verification steps.
+
+Use '--description' to record why the release was made, and '--label' to attach
+key/value labels that can later be used with 'helm list --selector':
+
+	$ helm install --description "initial rollout" --label team=payments redis
 `
 
 type installCmd struct {
 	name         string
 	namespace    string
-	valuesFile   string
+	valueFiles   valueFiles
 	chartPath    string
 	dryRun       bool
 	disableHooks bool
@@ -76,6 +85,8 @@ type installCmd struct {
 	client       helm.Interface
 	values       *values
 	nameTemplate string
+	description  string
+	labels       []string
 }
 
 func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -105,17 +116,19 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.StringVarP(&inst.valuesFile, "values", "f", "", "specify values in a YAML file")
+	f.VarP(&inst.valueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
 	f.StringVarP(&inst.name, "name", "n", "", "the release name. If unspecified, it will autogenerate one for you")
 	// TODO use kubeconfig default
 	f.StringVar(&inst.namespace, "namespace", "default", "the namespace to install the release into")
 	f.BoolVar(&inst.dryRun, "dry-run", false, "simulate an install")
 	f.BoolVar(&inst.disableHooks, "no-hooks", false, "prevent hooks from running during install")
 	f.BoolVar(&inst.replace, "replace", false, "re-use the given name, even if that name is already used. This is unsafe in production")
-	f.Var(inst.values, "set", "set values on the command line. Separate values with commas: key1=val1,key2=val2")
+	addSetFlags(f, inst.values)
 	f.StringVar(&inst.nameTemplate, "name-template", "", "specify template used to name the release")
 	f.BoolVar(&inst.verify, "verify", false, "verify the package before installing it")
 	f.StringVar(&inst.keyring, "keyring", defaultKeyring(), "location of public keys used for verification")
+	f.StringVar(&inst.description, "description", "", "a description of why the release is being installed")
+	f.StringSliceVar(&inst.labels, "label", []string{}, "labels to attach to the release. Separate labels with commas: key1=val1,key2=val2")
 	return cmd
 }
 
@@ -124,7 +137,12 @@ func (i *installCmd) run() error {
 		fmt.Fprintf(i.out, "Chart path: %s\n", i.chartPath)
 	}
 
-	rawVals, err := i.vals()
+	rawVals, err := mergeValues(i.valueFiles, i.values)
+	if err != nil {
+		return err
+	}
+
+	labels, err := parseLabels(i.labels)
 	if err != nil {
 		return err
 	}
@@ -146,7 +164,9 @@ func (i *installCmd) run() error {
 		helm.ReleaseName(i.name),
 		helm.InstallDryRun(i.dryRun),
 		helm.InstallReuseName(i.replace),
-		helm.InstallDisableHooks(i.disableHooks))
+		helm.InstallDisableHooks(i.disableHooks),
+		helm.InstallDescription(i.description),
+		helm.InstallLabels(labels))
 	if err != nil {
 		return prettyError(err)
 	}
@@ -171,31 +191,6 @@ func (i *installCmd) run() error {
 	return nil
 }
 
-func (i *installCmd) vals() ([]byte, error) {
-	var buffer bytes.Buffer
-
-	// User specified a values file via -f/--values
-	if i.valuesFile != "" {
-		bytes, err := ioutil.ReadFile(i.valuesFile)
-		if err != nil {
-			return []byte{}, err
-		}
-		buffer.Write(bytes)
-	}
-
-	// User specified value pairs via --set
-	// These override any values in the specified file
-	if len(i.values.pairs) > 0 {
-		bytes, err := i.values.yaml()
-		if err != nil {
-			return []byte{}, err
-		}
-		buffer.Write(bytes)
-	}
-
-	return buffer.Bytes(), nil
-}
-
 // printRelease prints info about a release if the flagDebug is true.
 func (i *installCmd) printRelease(rel *release.Release) {
 	if rel == nil {
@@ -213,61 +208,6 @@ func (i *installCmd) printRelease(rel *release.Release) {
 	}
 }
 
-// values represents the command-line value pairs
-type values struct {
-	pairs map[string]interface{}
-}
-
-func (v *values) yaml() ([]byte, error) {
-	return yaml.Marshal(v.pairs)
-}
-
-func (v *values) String() string {
-	out, _ := v.yaml()
-	return string(out)
-}
-
-func (v *values) Type() string {
-	// Added to pflags.Value interface, but not documented there.
-	return "struct"
-}
-
-func (v *values) Set(data string) error {
-	v.pairs = map[string]interface{}{}
-
-	items := strings.Split(data, ",")
-	for _, item := range items {
-		n, val := splitPair(item)
-		names := strings.Split(n, ".")
-		ln := len(names)
-		current := &v.pairs
-		for i := 0; i < ln; i++ {
-			if i+1 == ln {
-				// We're at the last element. Set it.
-				(*current)[names[i]] = val
-			} else {
-				//
-				if e, ok := (*current)[names[i]]; !ok {
-					m := map[string]interface{}{}
-					(*current)[names[i]] = m
-					current = &m
-				} else if m, ok := e.(map[string]interface{}); ok {
-					current = &m
-				}
-			}
-		}
-	}
-	return nil
-}
-
-func splitPair(item string) (name string, value interface{}) {
-	pair := strings.SplitN(item, "=", 2)
-	if len(pair) == 1 {
-		return pair[0], true
-	}
-	return pair[0], pair[1]
-}
-
 // locateChartPath looks for a chart directory in known places, and returns either the full path or an error.
 //
 // This does not ensure that the chart is well-formed; only that the requested filename exists.
@@ -52,6 +52,21 @@ func TestInstall(t *testing.T) {
 			resp:     releaseMock(&releaseOptions{name: "virgil"}),
 			expected: "virgil",
 		},
+		// Install, with description and labels
+		{
+			name:     "install with description and labels",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    []string{"--name", "aeneas", "--description", "first release", "--label", "team=payments,env=prod"},
+			expected: "aeneas",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		// Install, malformed label
+		{
+			name:  "install with malformed label",
+			args:  []string{"testdata/testcharts/alpine"},
+			flags: strings.Split("--name aeneas --label team", " "),
+			err:   true,
+		},
 		// Install, no charts
 		{
 			name: "install with no chart specified",
@@ -18,11 +18,13 @@ package installer // import "k8s.io/helm/cmd/helm/installer"
 
 import (
 	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/errors"
 	"k8s.io/kubernetes/pkg/apis/extensions"
+	client "k8s.io/kubernetes/pkg/client/unversioned"
 	"k8s.io/kubernetes/pkg/util/intstr"
 
 	"k8s.io/helm/pkg/kube"
@@ -31,15 +33,51 @@ import (
 
 const defaultImage = "gcr.io/kubernetes-helm/tiller"
 
+const (
+	// secretName is the name of the secret holding Tiller's TLS certificates.
+	secretName = "tiller-secret"
+	// certsDir is where the TLS certificates are mounted in the Tiller container.
+	certsDir = "/etc/certs"
+)
+
+// Options control how Tiller is installed.
+type Options struct {
+	// Namespace is the namespace Tiller is installed into. If empty, the
+	// namespace of the current kubeconfig context is used.
+	Namespace string
+	// Image is the Tiller image. If empty, the image matching the client
+	// version is used.
+	Image string
+	// AllowedNamespaces restricts the namespaces Tiller may install releases
+	// into. If empty, every namespace is allowed.
+	AllowedNamespaces []string
+
+	// EnableTLS starts Tiller with TLS, using TLSCertFile and TLSKeyFile.
+	EnableTLS bool
+	// VerifyTLS requires clients to present a certificate signed by the CA
+	// in TLSCaCertFile. It implies EnableTLS.
+	VerifyTLS bool
+	// TLSCertFile is the path to Tiller's TLS certificate.
+	TLSCertFile string
+	// TLSKeyFile is the path to the key of TLSCertFile.
+	TLSKeyFile string
+	// TLSCaCertFile is the path to the CA certificate used to verify clients.
+	TLSCaCertFile string
+}
+
+func (opts *Options) tls() bool {
+	return opts.EnableTLS || opts.VerifyTLS
+}
+
 // Install uses kubernetes client to install tiller
 //
-// Returns the string output received from the operation, and an error if the
-// command failed.
-//
-// If verbose is true, this will print the manifest to stdout.
-func Install(namespace, image string, verbose bool) error {
+// Returns an error if the command failed. If TLS is enabled, the certificates
+// are stored in a secret that is mounted into the Tiller container.
+func Install(opts *Options) error {
 	kc := kube.New(nil)
 
+	namespace, image := opts.Namespace, opts.Image
+
 	if namespace == "" {
 		ns, _, err := kc.DefaultNamespace()
 		if err != nil {
@@ -66,7 +104,13 @@ func Install(namespace, image string, verbose bool) error {
 		image = fmt.Sprintf("%s:%s", defaultImage, tag)
 	}
 
-	rc := generateDeployment(image)
+	if opts.tls() {
+		if err := installSecret(c, namespace, opts); err != nil {
+			return err
+		}
+	}
+
+	rc := generateDeployment(image, opts)
 
 	_, err = c.Deployments(namespace).Create(rc)
 	return err
@@ -77,7 +121,7 @@ func generateLabels(labels map[string]string) map[string]string {
 	return labels
 }
 
-func generateDeployment(image string) *extensions.Deployment {
+func generateDeployment(image string, opts *Options) *extensions.Deployment {
 	labels := generateLabels(map[string]string{"name": "tiller"})
 	d := &extensions.Deployment{
 		ObjectMeta: api.ObjectMeta{
@@ -97,6 +141,14 @@ func generateDeployment(image string) *extensions.Deployment {
 							Image:           image,
 							ImagePullPolicy: "Always",
 							Ports:           []api.ContainerPort{{ContainerPort: 44134, Name: "tiller"}},
+							Env: []api.EnvVar{
+								{
+									Name: "TILLER_NAMESPACE",
+									ValueFrom: &api.EnvVarSource{
+										FieldRef: &api.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+									},
+								},
+							},
 							LivenessProbe: &api.Probe{
 								Handler: api.Handler{
 									HTTPGet: &api.HTTPGetAction{
@@ -123,9 +175,91 @@ func generateDeployment(image string) *extensions.Deployment {
 			},
 		},
 	}
+	var args []string
+	if len(opts.AllowedNamespaces) > 0 {
+		args = append(args, "--allowed-namespaces="+strings.Join(opts.AllowedNamespaces, ","))
+	}
+	if opts.tls() {
+		args = append(args, addTLS(&d.Spec.Template.Spec, opts)...)
+	}
+	if len(args) > 0 {
+		d.Spec.Template.Spec.Containers[0].Command = append([]string{"/tiller"}, args...)
+	}
 	return d
 }
 
+// addTLS mounts the certificates from the secret into the Tiller container,
+// and returns the arguments Tiller needs to serve TLS with them.
+func addTLS(spec *api.PodSpec, opts *Options) []string {
+	args := []string{"--tls"}
+	if opts.VerifyTLS {
+		args = append(args, "--tls-verify")
+	}
+	container := &spec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, api.VolumeMount{
+		Name:      "tiller-certs",
+		MountPath: certsDir,
+		ReadOnly:  true,
+	})
+	spec.Volumes = append(spec.Volumes, api.Volume{
+		Name: "tiller-certs",
+		VolumeSource: api.VolumeSource{
+			Secret: &api.SecretVolumeSource{SecretName: secretName},
+		},
+	})
+	return args
+}
+
+// generateSecret builds the secret holding Tiller's TLS certificates, using
+// the file names Tiller expects by default.
+// installSecret stores the TLS certificates in Tiller's secret. If the secret
+// already exists, it is replaced, so that running init again rotates the
+// certificates.
+func installSecret(c client.SecretsNamespacer, namespace string, opts *Options) error {
+	secret, err := generateSecret(opts)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Secrets(namespace).Create(secret); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return err
+		}
+		_, err = c.Secrets(namespace).Update(secret)
+		return err
+	}
+	return nil
+}
+
+func generateSecret(opts *Options) (*api.Secret, error) {
+	files := map[string]string{
+		"tls.crt": opts.TLSCertFile,
+		"tls.key": opts.TLSKeyFile,
+	}
+	if opts.VerifyTLS {
+		files["ca.crt"] = opts.TLSCaCertFile
+	}
+
+	secret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{
+			Name:   secretName,
+			Labels: generateLabels(map[string]string{"name": "tiller"}),
+		},
+		Type: api.SecretTypeOpaque,
+		Data: map[string][]byte{},
+	}
+	for key, path := range files {
+		if path == "" {
+			return nil, fmt.Errorf("missing TLS file for %s", key)
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		secret.Data[key] = b
+	}
+	return secret, nil
+}
+
 func generateNamespace(namespace string) *api.Namespace {
 	return &api.Namespace{
 		ObjectMeta: api.ObjectMeta{
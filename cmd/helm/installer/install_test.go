@@ -0,0 +1,163 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package installer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/client/unversioned/testclient"
+	"k8s.io/kubernetes/pkg/runtime"
+)
+
+func TestGenerateDeployment(t *testing.T) {
+	d := generateDeployment("tiller:test", &Options{})
+	spec := d.Spec.Template.Spec
+	if spec.Containers[0].Image != "tiller:test" {
+		t.Errorf("expected image tiller:test, got %q", spec.Containers[0].Image)
+	}
+	if len(spec.Volumes) != 0 || spec.Containers[0].Command != nil {
+		t.Errorf("expected no TLS configuration, got %v", spec)
+	}
+}
+
+func TestGenerateDeploymentTLS(t *testing.T) {
+	d := generateDeployment("tiller:test", &Options{VerifyTLS: true})
+	spec := d.Spec.Template.Spec
+	c := spec.Containers[0]
+
+	expect := []string{"/tiller", "--tls", "--tls-verify"}
+	if !reflect.DeepEqual(c.Command, expect) {
+		t.Errorf("expected command %v, got %v", expect, c.Command)
+	}
+	if len(c.VolumeMounts) != 1 || c.VolumeMounts[0].MountPath != certsDir {
+		t.Errorf("expected certificates mounted at %s, got %v", certsDir, c.VolumeMounts)
+	}
+	if len(spec.Volumes) != 1 || spec.Volumes[0].Secret == nil || spec.Volumes[0].Secret.SecretName != secretName {
+		t.Errorf("expected a volume for secret %s, got %v", secretName, spec.Volumes)
+	}
+}
+
+func TestGenerateDeploymentNamespaces(t *testing.T) {
+	d := generateDeployment("tiller:test", &Options{AllowedNamespaces: []string{"team-a", "team-b"}})
+	c := d.Spec.Template.Spec.Containers[0]
+
+	expect := []string{"/tiller", "--allowed-namespaces=team-a,team-b"}
+	if !reflect.DeepEqual(c.Command, expect) {
+		t.Errorf("expected command %v, got %v", expect, c.Command)
+	}
+	if len(c.Env) != 1 || c.Env[0].Name != "TILLER_NAMESPACE" || c.Env[0].ValueFrom.FieldRef.FieldPath != "metadata.namespace" {
+		t.Errorf("expected TILLER_NAMESPACE from the downward API, got %v", c.Env)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-installer-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	opts := &Options{
+		VerifyTLS:     true,
+		TLSCertFile:   write("cert.pem"),
+		TLSKeyFile:    write("key.pem"),
+		TLSCaCertFile: write("ca.pem"),
+	}
+
+	secret, err := generateSecret(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{"tls.crt": "cert.pem", "tls.key": "key.pem", "ca.crt": "ca.pem"}
+	for key, data := range expect {
+		if string(secret.Data[key]) != data {
+			t.Errorf("expected %s to hold %q, got %q", key, data, secret.Data[key])
+		}
+	}
+
+	opts.TLSCaCertFile = ""
+	if _, err := generateSecret(opts); err == nil {
+		t.Error("expected an error for a missing CA certificate")
+	}
+}
+
+func TestInstallSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-installer-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	opts := &Options{
+		EnableTLS:   true,
+		TLSCertFile: write("cert.pem", "old cert"),
+		TLSKeyFile:  write("key.pem", "old key"),
+	}
+
+	// The fake client does not store objects, so it is told what to return.
+	c := testclient.NewSimpleFake()
+	c.PrependReactor("create", "secrets", func(a testclient.Action) (bool, runtime.Object, error) {
+		return true, a.(testclient.CreateAction).GetObject(), nil
+	})
+	if err := installSecret(c, "default", opts); err != nil {
+		t.Fatal(err)
+	}
+	if actions := c.Actions(); len(actions) != 1 || !actions[0].Matches("create", "secrets") {
+		t.Errorf("expected the secret to be created, got %v", actions)
+	}
+
+	// Installing again replaces the certificates.
+	c = testclient.NewSimpleFake()
+	c.PrependReactor("create", "secrets", func(testclient.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewAlreadyExists(api.Resource("secrets"), secretName)
+	})
+	c.PrependReactor("update", "secrets", func(a testclient.Action) (bool, runtime.Object, error) {
+		return true, a.(testclient.UpdateAction).GetObject(), nil
+	})
+	opts.TLSCertFile = write("cert.pem", "new cert")
+	if err := installSecret(c, "default", opts); err != nil {
+		t.Fatalf("expected the secret to be replaced, got %s", err)
+	}
+	actions := c.Actions()
+	if len(actions) != 2 || !actions[1].Matches("update", "secrets") {
+		t.Fatalf("expected the secret to be updated, got %v", actions)
+	}
+	secret := actions[1].(testclient.UpdateAction).GetObject().(*api.Secret)
+	if string(secret.Data["tls.crt"]) != "new cert" {
+		t.Errorf("expected the new certificate, got %q", secret.Data["tls.crt"])
+	}
+}
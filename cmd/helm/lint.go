@@ -47,10 +47,17 @@ var lintCommand = &cobra.Command{
 	RunE:  lintCmd,
 }
 
-var flagStrict bool
+var (
+	flagStrict     bool
+	lintValueFiles valueFiles
+	lintValues     = new(values)
+)
 
 func init() {
-	lintCommand.Flags().BoolVarP(&flagStrict, "strict", "", false, "fail on lint warnings")
+	f := lintCommand.Flags()
+	f.BoolVarP(&flagStrict, "strict", "", false, "fail on lint warnings")
+	f.VarP(&lintValueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
+	addSetFlags(f, lintValues)
 	RootCommand.AddCommand(lintCommand)
 }
 
@@ -69,10 +76,15 @@ func lintCmd(cmd *cobra.Command, args []string) error {
 		lowestTolerance = support.ErrorSev
 	}
 
+	rawVals, err := mergeValues(lintValueFiles, lintValues)
+	if err != nil {
+		return err
+	}
+
 	var total int
 	var failures int
 	for _, path := range paths {
-		if linter, err := lintChart(path); err != nil {
+		if linter, err := lintChart(path, rawVals); err != nil {
 			fmt.Println("==> Skipping", path)
 			fmt.Println(err)
 		} else {
@@ -104,7 +116,9 @@ func lintCmd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func lintChart(path string) (support.Linter, error) {
+// lintChart lints the chart at path, which may be a directory or an archive,
+// rendering it with vals overriding its default values.
+func lintChart(path string, vals []byte) (support.Linter, error) {
 	var chartPath string
 	linter := support.Linter{}
 
@@ -136,5 +150,8 @@ func lintChart(path string) (support.Linter, error) {
 		return linter, errLintNoChart
 	}
 
-	return lint.All(chartPath), nil
+	if len(vals) == 0 {
+		return lint.All(chartPath), nil
+	}
+	return lint.AllWithValues(chartPath, vals), nil
 }
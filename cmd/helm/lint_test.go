@@ -18,6 +18,8 @@ package main
 
 import (
 	"testing"
+
+	"k8s.io/helm/pkg/lint/support"
 )
 
 var (
@@ -26,12 +28,34 @@ var (
 )
 
 func TestLintChart(t *testing.T) {
-	if _, err := lintChart(chartDirPath); err != nil {
+	if _, err := lintChart(chartDirPath, nil); err != nil {
 		t.Errorf("%s", err)
 	}
 
-	if _, err := lintChart(archivedChartPath); err != nil {
+	if _, err := lintChart(archivedChartPath, nil); err != nil {
 		t.Errorf("%s", err)
 	}
 
 }
+
+func TestLintChartValues(t *testing.T) {
+	v := new(values)
+	if err := v.Set("replicaCount=1"); err != nil {
+		t.Fatal(err)
+	}
+	rawVals, err := mergeValues(nil, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linter, err := lintChart(chartDirPath, rawVals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linter.HighestSeverity >= support.ErrorSev {
+		t.Errorf("Expected no lint errors, got %v", linter.Messages)
+	}
+	if string(linter.Values) != "replicaCount: 1\n" {
+		t.Errorf("Expected the values to be linted with, got %q", linter.Values)
+	}
+}
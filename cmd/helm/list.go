@@ -50,6 +50,12 @@ Only items that match the filter will be returned.
 If no results are found, 'helm list' will exit 0, but with no output (or in
 the case of '-l', only headers).
 
+Releases can also be narrowed down by the namespace they were installed into,
+by chart name and version range, and by release labels:
+
+	$ helm list --namespace payments --chart nginx --chart-version '^1.2.0'
+	$ helm list --selector team=payments,env=prod
+
 By default, up to 256 items may be returned. To limit this, use the '--max' flag.
 Setting '--max' to 0 will not return all results. Rather, it will return the
 server's default, which may be much higher than 256. Pairing the '--max'
@@ -57,19 +63,24 @@ flag with the '--offset' flag allows you to page through results.
 `
 
 type listCmd struct {
-	filter     string
-	short      bool
-	limit      int
-	offset     string
-	byDate     bool
-	sortDesc   bool
-	out        io.Writer
-	all        bool
-	deleted    bool
-	deployed   bool
-	failed     bool
-	superseded bool
-	client     helm.Interface
+	filter      string
+	short       bool
+	limit       int
+	offset      string
+	byDate      bool
+	sortDesc    bool
+	out         io.Writer
+	all         bool
+	deleted     bool
+	deployed    bool
+	failed      bool
+	interrupted bool
+	superseded  bool
+	namespace   string
+	chart       string
+	chartVers   string
+	selector    string
+	client      helm.Interface
 }
 
 func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -88,7 +99,7 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 				list.filter = strings.Join(args, " ")
 			}
 			if list.client == nil {
-				list.client = helm.NewClient(helm.Host(tillerHost))
+				list.client = newClient()
 			}
 			return list.run()
 		},
@@ -103,6 +114,11 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&list.deleted, "deleted", false, "show deleted releases")
 	f.BoolVar(&list.deployed, "deployed", false, "show deployed releases. If no other is specified, this will be automatically enabled")
 	f.BoolVar(&list.failed, "failed", false, "show failed releases")
+	f.BoolVar(&list.interrupted, "interrupted", false, "show releases whose last operation was interrupted by Tiller stopping")
+	f.StringVar(&list.namespace, "namespace", "", "show releases within a specific namespace")
+	f.StringVar(&list.chart, "chart", "", "show releases of the named chart")
+	f.StringVar(&list.chartVers, "chart-version", "", "show releases whose chart version satisfies a semver range, e.g. '^1.2.0'")
+	f.StringVar(&list.selector, "selector", "", "show releases matching a comma separated list of labels, e.g. 'team=payments,env=prod'")
 	// TODO: Do we want this as a feature of 'helm list'?
 	//f.BoolVar(&list.superseded, "history", true, "show historical releases")
 	return cmd
@@ -121,6 +137,11 @@ func (l *listCmd) run() error {
 
 	stats := l.statusCodes()
 
+	labels, err := parseSelector(l.selector)
+	if err != nil {
+		return err
+	}
+
 	res, err := l.client.ListReleases(
 		helm.ReleaseListLimit(l.limit),
 		helm.ReleaseListOffset(l.offset),
@@ -128,6 +149,10 @@ func (l *listCmd) run() error {
 		helm.ReleaseListSort(int32(sortBy)),
 		helm.ReleaseListOrder(int32(sortOrder)),
 		helm.ReleaseListStatuses(stats),
+		helm.ReleaseListNamespace(l.namespace),
+		helm.ReleaseListChart(l.chart),
+		helm.ReleaseListChartVersion(l.chartVers),
+		helm.ReleaseListLabels(labels),
 	)
 
 	if err != nil {
@@ -165,6 +190,7 @@ func (l *listCmd) statusCodes() []release.Status_Code {
 			// that were replaced by an upgrade.
 			//release.Status_SUPERSEDED,
 			release.Status_FAILED,
+			release.Status_INTERRUPTED,
 		}
 	}
 	status := []release.Status_Code{}
@@ -177,6 +203,9 @@ func (l *listCmd) statusCodes() []release.Status_Code {
 	if l.failed {
 		status = append(status, release.Status_FAILED)
 	}
+	if l.interrupted {
+		status = append(status, release.Status_INTERRUPTED)
+	}
 	if l.superseded {
 		status = append(status, release.Status_SUPERSEDED)
 	}
@@ -188,6 +217,14 @@ func (l *listCmd) statusCodes() []release.Status_Code {
 	return status
 }
 
+// parseSelector parses a comma separated list of key=value pairs into a map.
+func parseSelector(selector string) (map[string]string, error) {
+	if selector == "" {
+		return map[string]string{}, nil
+	}
+	return parseLabels(strings.Split(selector, ","))
+}
+
 func formatList(rels []*release.Release) string {
 	table := uitable.New()
 	table.MaxColWidth = 30
@@ -68,6 +68,19 @@ func TestListCmd(t *testing.T) {
 			// See note on previous test.
 			expected: "thomas-guide\natlas-guide",
 		},
+		{
+			name: "with a selector",
+			args: []string{"--selector", "team=payments,env=prod", "-q"},
+			resp: []*release.Release{
+				releaseMock(&releaseOptions{name: "atlas"}),
+			},
+			expected: "atlas",
+		},
+		{
+			name: "with an invalid selector",
+			args: []string{"--selector", "team"},
+			err:  true,
+		},
 	}
 
 	var buf bytes.Buffer
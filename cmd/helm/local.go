@@ -0,0 +1,70 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+const (
+	// localHost is the value of --host or $HELM_HOST that selects local mode.
+	localHost = "local"
+
+	storageMemory    = "memory"
+	storageConfigMap = "configmap"
+)
+
+var (
+	// flagLocal runs the release logic in-process instead of in Tiller.
+	flagLocal    bool
+	localStorage = storageConfigMap
+
+	// localServer is the in-process release server used in local mode.
+	localServer *tiller.ReleaseServer
+)
+
+// isLocal reports whether releases are managed in-process rather than by a
+// Tiller in the cluster.
+func isLocal() bool {
+	return flagLocal || tillerHost == localHost
+}
+
+// setupLocal creates the in-process release server. It uses the current
+// kubeconfig, and stores releases in the Tiller namespace.
+func setupLocal() error {
+	env := environment.New()
+	env.Namespace = tillerNamespace
+	switch localStorage {
+	case storageMemory:
+		env.Releases = storage.Init(driver.NewMemory())
+	case storageConfigMap:
+		c, err := env.KubeClient.APIClient()
+		if err != nil {
+			return fmt.Errorf("cannot initialize Kubernetes connection: %s", err)
+		}
+		env.Releases = storage.Init(driver.NewConfigMaps(c.ConfigMaps(env.Namespace)))
+	default:
+		return fmt.Errorf("unknown storage driver %q: expected %q or %q", localStorage, storageConfigMap, storageMemory)
+	}
+	localServer = tiller.NewReleaseServer(env)
+	return nil
+}
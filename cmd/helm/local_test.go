@@ -0,0 +1,55 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+)
+
+func TestSetupLocal(t *testing.T) {
+	defer func(s string) { localStorage = s }(localStorage)
+
+	localStorage = storageMemory
+	if err := setupLocal(); err != nil {
+		t.Fatalf("Failed to set up local mode: %s", err)
+	}
+	if localServer == nil {
+		t.Error("Expected the local release server to be set")
+	}
+
+	localStorage = "etcd"
+	if err := setupLocal(); err == nil {
+		t.Error("Expected an error for an unknown storage driver")
+	}
+}
+
+func TestIsLocal(t *testing.T) {
+	defer func(h string, l bool) { tillerHost, flagLocal = h, l }(tillerHost, flagLocal)
+
+	tillerHost, flagLocal = ":44134", false
+	if isLocal() {
+		t.Error("Expected a Tiller host not to be local")
+	}
+	tillerHost = localHost
+	if !isLocal() {
+		t.Error("Expected HELM_HOST=local to be local")
+	}
+	tillerHost, flagLocal = "", true
+	if !isLocal() {
+		t.Error("Expected --local to be local")
+	}
+}
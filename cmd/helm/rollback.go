@@ -34,6 +34,8 @@ type rollbackCmd struct {
 	name         string
 	dryRun       bool
 	disableHooks bool
+	description  string
+	labels       []string
 	out          io.Writer
 	client       helm.Interface
 }
@@ -62,11 +64,23 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	f.BoolVar(&rollback.dryRun, "dry-run", false, "simulate a rollback")
 	f.BoolVar(&rollback.disableHooks, "no-hooks", false, "prevent hooks from running during rollback")
+	f.StringVar(&rollback.description, "description", "", "a description of why the release is being rolled back")
+	f.StringSliceVar(&rollback.labels, "label", []string{}, "labels to add to the release. Separate labels with commas: key1=val1,key2=val2")
 	return cmd
 }
 
 func (r *rollbackCmd) run() error {
-	_, err := r.client.RollbackRelease(r.name, helm.RollbackDryRun(r.dryRun), helm.RollbackDisableHooks(r.disableHooks))
+	labels, err := parseLabels(r.labels)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.RollbackRelease(
+		r.name,
+		helm.RollbackDryRun(r.dryRun),
+		helm.RollbackDisableHooks(r.disableHooks),
+		helm.RollbackDescription(r.description),
+		helm.RollbackLabels(labels))
 	if err != nil {
 		return prettyError(err)
 	}
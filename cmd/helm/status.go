@@ -19,6 +19,8 @@ package main
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -54,7 +56,7 @@ func newStatusCmd(client helm.Interface, out io.Writer) *cobra.Command {
 			}
 			status.release = args[0]
 			if status.client == nil {
-				status.client = helm.NewClient(helm.Host(tillerHost))
+				status.client = newClient()
 			}
 			return status.run()
 		},
@@ -83,6 +85,12 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 	}
 	fmt.Fprintf(out, "Namespace: %s\n", res.Namespace)
 	fmt.Fprintf(out, "Status: %s\n", res.Info.Status.Code)
+	if res.Info.Description != "" {
+		fmt.Fprintf(out, "Description: %s\n", res.Info.Description)
+	}
+	if len(res.Info.Labels) > 0 {
+		fmt.Fprintf(out, "Labels: %s\n", formatLabels(res.Info.Labels))
+	}
 	if res.Info.Status.Details != nil {
 		fmt.Fprintf(out, "Details: %s\n", res.Info.Status.Details)
 	}
@@ -94,3 +102,13 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 		fmt.Fprintf(out, "Notes:\n%s\n", res.Info.Status.Notes)
 	}
 }
+
+// formatLabels renders labels as a sorted, comma separated list of key=value pairs.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+const templateDesc = `
+This command renders a chart's templates locally and displays the output.
+
+Values are set in the same way as for 'helm install', and the templates are
+rendered as Tiller would render them, without contacting Tiller or Kubernetes.
+NOTES.txt, partials and empty templates are not displayed.
+
+To render only some of the templates, pass their paths within the chart:
+
+	$ helm template -x templates/deployment.yaml -x templates/service.yaml mychart
+
+With '--output-dir', each template is written to its own file in the directory
+instead of to stdout.
+`
+
+type templateCmd struct {
+	name       string
+	namespace  string
+	valueFiles valueFiles
+	chartPath  string
+	execute    []string
+	outputDir  string
+	out        io.Writer
+	values     *values
+}
+
+func newTemplateCmd(out io.Writer) *cobra.Command {
+	t := &templateCmd{
+		out:    out,
+		values: new(values),
+	}
+
+	cmd := &cobra.Command{
+		Use:   "template [CHART]",
+		Short: "locally render templates",
+		Long:  templateDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkArgsLength(len(args), "chart name"); err != nil {
+				return err
+			}
+			cp, err := locateChartPath(args[0], false, "")
+			if err != nil {
+				return err
+			}
+			t.chartPath = cp
+			return t.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.VarP(&t.valueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
+	addSetFlags(f, t.values)
+	f.StringVarP(&t.name, "name", "n", "RELEASE-NAME", "the release name used to render the templates")
+	f.StringVar(&t.namespace, "namespace", "default", "the namespace used to render the templates")
+	f.StringSliceVarP(&t.execute, "execute", "x", []string{}, "only render the templates at these paths within the chart")
+	f.StringVar(&t.outputDir, "output-dir", "", "write each template to a file in this directory instead of to stdout")
+	return cmd
+}
+
+func (t *templateCmd) run() error {
+	ch, err := chartutil.Load(t.chartPath)
+	if err != nil {
+		return err
+	}
+
+	rawVals, err := mergeValues(t.valueFiles, t.values)
+	if err != nil {
+		return err
+	}
+	options := chartutil.ReleaseOptions{Name: t.name, Time: timeconv.Now(), Namespace: t.namespace}
+	vals, err := chartutil.ToRenderValues(ch, &chart.Config{Raw: string(rawVals)}, options, chartutil.DefaultCapabilities)
+	if err != nil {
+		return err
+	}
+
+	hooks, manifests, _, err := tiller.Render(engine.New(), ch, vals, nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		manifests = append(manifests, tiller.Manifest{Name: h.Path, Content: h.Manifest})
+	}
+
+	manifests, err = t.selected(ch, manifests)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range manifests {
+		if t.outputDir != "" {
+			if err := writeManifest(t.outputDir, m); err != nil {
+				return err
+			}
+			continue
+		}
+		fmt.Fprintf(t.out, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return nil
+}
+
+// selected returns the manifests rendered from the templates passed with
+// --execute, or all of them if there are none.
+func (t *templateCmd) selected(ch *chart.Chart, manifests []tiller.Manifest) ([]tiller.Manifest, error) {
+	if len(t.execute) == 0 {
+		return manifests, nil
+	}
+	byName := map[string]tiller.Manifest{}
+	for _, m := range manifests {
+		byName[m.Name] = m
+	}
+	res := []tiller.Manifest{}
+	for _, x := range t.execute {
+		m, ok := byName[path.Join(ch.Metadata.Name, filepath.ToSlash(x))]
+		if !ok {
+			return nil, fmt.Errorf("could not find template %s in chart", x)
+		}
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// writeManifest writes a rendered template to its path under dir. Templates
+// whose path would lead outside dir, such as "../x", are refused.
+func writeManifest(dir string, m tiller.Manifest) error {
+	p := filepath.Join(dir, filepath.FromSlash(m.Name))
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("template %s would be written outside of %s", m.Name, dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p, []byte(m.Content), 0644)
+}
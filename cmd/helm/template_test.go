@@ -0,0 +1,139 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"k8s.io/helm/pkg/tiller"
+)
+
+func TestTemplateCmd(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		flags  map[string]string
+		expect string
+		err    bool
+	}{
+		{
+			name:   "template without chart",
+			args:   []string{},
+			expect: "This command needs 1 argument: chart name",
+			err:    true,
+		},
+		{
+			name:   "template with defaults",
+			args:   []string{"testdata/testcharts/alpine"},
+			expect: `# Source: alpine/templates/alpine-pod.yaml\n(.|\n)*name: "RELEASE-NAME-my-alpine"`,
+		},
+		{
+			name:   "template with name and values",
+			args:   []string{"testdata/testcharts/alpine"},
+			flags:  map[string]string{"name": "foo", "set": "Name=bar"},
+			expect: `name: "foo-bar"`,
+		},
+		{
+			name:   "template a single file",
+			args:   []string{"testdata/testcharts/alpine"},
+			flags:  map[string]string{"execute": "templates/alpine-pod.yaml"},
+			expect: "# Source: alpine/templates/alpine-pod.yaml",
+		},
+		{
+			name:   "template a missing file",
+			args:   []string{"testdata/testcharts/alpine"},
+			flags:  map[string]string{"execute": "templates/missing.yaml"},
+			expect: "could not find template templates/missing.yaml in chart",
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		buf := bytes.NewBuffer(nil)
+		c := newTemplateCmd(buf)
+		setFlags(c, tt.flags)
+		re := regexp.MustCompile(tt.expect)
+
+		err := c.RunE(c, tt.args)
+		if err != nil {
+			if !tt.err || !re.MatchString(err.Error()) {
+				t.Errorf("%q: expected error %q, got %q", tt.name, tt.expect, err)
+			}
+			continue
+		}
+		if tt.err {
+			t.Errorf("%q: expected error %q", tt.name, tt.expect)
+			continue
+		}
+		if !re.Match(buf.Bytes()) {
+			t.Errorf("%q: expected output %q, got %q", tt.name, tt.expect, buf.String())
+		}
+	}
+}
+
+func TestTemplateCmdOutputDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-template-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buf := bytes.NewBuffer(nil)
+	c := newTemplateCmd(buf)
+	setFlags(c, map[string]string{"output-dir": dir})
+	if err := c.RunE(c, []string{"testdata/testcharts/alpine"}); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output, got %q", buf.String())
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "alpine", "templates", "alpine-pod.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(b, []byte("kind: Pod")) {
+		t.Errorf("Expected the rendered pod, got %q", b)
+	}
+}
+
+func TestWriteManifestOutsideDir(t *testing.T) {
+	parent, err := ioutil.TempDir("", "helm-template-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(parent)
+	dir := filepath.Join(parent, "out")
+
+	for _, name := range []string{"alpine/../../escape.yaml", "../escape.yaml", "alpine/templates/../../.."} {
+		if err := writeManifest(dir, tiller.Manifest{Name: name, Content: "kind: Pod"}); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(parent, "escape.yaml")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file outside the output directory, got %v", err)
+	}
+
+	if err := writeManifest(dir, tiller.Manifest{Name: "alpine/templates/../pod.yaml", Content: "kind: Pod"}); err != nil {
+		t.Errorf("Expected a path inside the output directory to be written, got %s", err)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/tlsutil"
+)
+
+var (
+	tlsEnable     bool
+	tlsVerify     bool
+	tlsCaCertFile string
+	tlsCertFile   string
+	tlsKeyFile    string
+	tlsServerName string
+
+	// tlsConfig is the TLS configuration used to connect to Tiller, or nil
+	// if TLS is not enabled.
+	tlsConfig *tls.Config
+)
+
+func addTLSFlags(p *pflag.FlagSet) {
+	p.BoolVar(&tlsEnable, "tls", false, "enable TLS for the connection to tiller")
+	p.BoolVar(&tlsVerify, "tls-verify", false, "enable TLS and verify tiller's certificate. Implies --tls")
+	p.StringVar(&tlsCaCertFile, "tls-ca-cert", "", "path to the CA certificate used to verify tiller (default \"$HELM_HOME/ca.pem\")")
+	p.StringVar(&tlsCertFile, "tls-cert", "", "path to the TLS certificate presented to tiller (default \"$HELM_HOME/cert.pem\" with --tls-verify)")
+	p.StringVar(&tlsKeyFile, "tls-key", "", "path to the key of the TLS certificate (default \"$HELM_HOME/key.pem\" with --tls-verify)")
+	p.StringVar(&tlsServerName, "tls-hostname", "", "the server name used to verify tiller's certificate (default: the host of --host, or \"localhost\")")
+}
+
+// setupTLS loads the TLS configuration for the connection to Tiller if TLS is
+// enabled.
+func setupTLS() error {
+	if !tlsEnable && !tlsVerify {
+		return nil
+	}
+	cfg, err := tlsutil.ClientConfig(tlsOptions())
+	if err != nil {
+		return err
+	}
+	tlsConfig = cfg
+	return nil
+}
+
+// tlsOptions returns the options for the TLS connection to Tiller.
+//
+// The files in $HELM_HOME are only used by default when verification is on.
+// With just --tls, a client certificate is presented only if one is given.
+func tlsOptions() tlsutil.Options {
+	opts := tlsutil.Options{
+		CaCertFile: tlsCaCertFile,
+		CertFile:   tlsCertFile,
+		KeyFile:    tlsKeyFile,
+		Verify:     tlsVerify,
+		ServerName: tlsHostname(),
+	}
+	if tlsVerify {
+		opts.CaCertFile = homeFile(tlsCaCertFile, "ca.pem")
+		opts.CertFile = homeFile(tlsCertFile, "cert.pem")
+		opts.KeyFile = homeFile(tlsKeyFile, "key.pem")
+	}
+	return opts
+}
+
+// homeFile returns path, or the named file in $HELM_HOME if path is empty.
+func homeFile(path, name string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(homePath(), name)
+}
+
+// tlsHostname returns the name tiller's certificate is verified against.
+func tlsHostname() string {
+	if tlsServerName != "" {
+		return tlsServerName
+	}
+	if host, _, err := net.SplitHostPort(tillerHost); err == nil && host != "" {
+		return host
+	}
+	return "localhost"
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTLSOptions(t *testing.T) {
+	defer func(home, cert, key string, verify bool) {
+		helmHome, tlsCertFile, tlsKeyFile, tlsVerify = home, cert, key, verify
+	}(helmHome, tlsCertFile, tlsKeyFile, tlsVerify)
+	helmHome = "/helm"
+
+	tests := []struct {
+		name     string
+		verify   bool
+		cert     string
+		key      string
+		expectCA string
+		expect   [2]string
+	}{
+		{"tls only", false, "", "", "", [2]string{"", ""}},
+		{"tls with a client certificate", false, "c.pem", "k.pem", "", [2]string{"c.pem", "k.pem"}},
+		{"verify", true, "", "", filepath.Join("/helm", "ca.pem"), [2]string{filepath.Join("/helm", "cert.pem"), filepath.Join("/helm", "key.pem")}},
+		{"verify with a client certificate", true, "c.pem", "k.pem", filepath.Join("/helm", "ca.pem"), [2]string{"c.pem", "k.pem"}},
+	}
+
+	for _, tt := range tests {
+		tlsVerify, tlsCertFile, tlsKeyFile = tt.verify, tt.cert, tt.key
+		opts := tlsOptions()
+		if opts.CaCertFile != tt.expectCA {
+			t.Errorf("%s: expected CA %q, got %q", tt.name, tt.expectCA, opts.CaCertFile)
+		}
+		if got := [2]string{opts.CertFile, opts.KeyFile}; got != tt.expect {
+			t.Errorf("%s: expected certificate and key %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+}
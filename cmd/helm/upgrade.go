@@ -17,10 +17,8 @@ limitations under the License.
 package main
 
 import (
-	"bytes"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -37,6 +35,9 @@ argument can be a relative path to a packaged or unpackaged chart.
 
 To override values in a chart, use either the '--values' flag and pass in a file
 or use the '--set' flag and pass configuration from the command line.
+
+Use '--description' to record why the upgrade was made. Labels given with
+'--label' are added to the labels of the release.
 `
 
 type upgradeCmd struct {
@@ -46,12 +47,14 @@ type upgradeCmd struct {
 	client       helm.Interface
 	dryRun       bool
 	disableHooks bool
-	valuesFile   string
+	valueFiles   valueFiles
 	values       *values
 	verify       bool
 	keyring      string
 	install      bool
 	namespace    string
+	description  string
+	labels       []string
 }
 
 func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -81,14 +84,16 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.StringVarP(&upgrade.valuesFile, "values", "f", "", "path to a values YAML file")
+	f.VarP(&upgrade.valueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
 	f.BoolVar(&upgrade.dryRun, "dry-run", false, "simulate an upgrade")
-	f.Var(upgrade.values, "set", "set values on the command line. Separate values with commas: key1=val1,key2=val2")
+	addSetFlags(f, upgrade.values)
 	f.BoolVar(&upgrade.disableHooks, "disable-hooks", false, "disable pre/post upgrade hooks")
 	f.BoolVar(&upgrade.verify, "verify", false, "verify the provenance of the chart before upgrading")
 	f.StringVar(&upgrade.keyring, "keyring", defaultKeyring(), "the path to the keyring that contains public singing keys")
 	f.BoolVarP(&upgrade.install, "install", "i", false, "if a release by this name doesn't already exist, run an install")
 	f.StringVar(&upgrade.namespace, "namespace", "default", "the namespace to install the release into (only used if --install is set)")
+	f.StringVar(&upgrade.description, "description", "", "a description of why the release is being upgraded")
+	f.StringSliceVar(&upgrade.labels, "label", []string{}, "labels to add to the release. Separate labels with commas: key1=val1,key2=val2")
 
 	return cmd
 }
@@ -114,24 +119,38 @@ func (u *upgradeCmd) run() error {
 				client:       u.client,
 				out:          u.out,
 				name:         u.release,
-				valuesFile:   u.valuesFile,
+				valueFiles:   u.valueFiles,
 				dryRun:       u.dryRun,
 				verify:       u.verify,
 				disableHooks: u.disableHooks,
 				keyring:      u.keyring,
 				values:       u.values,
 				namespace:    u.namespace,
+				description:  u.description,
+				labels:       u.labels,
 			}
 			return ic.run()
 		}
 	}
 
-	rawVals, err := u.vals()
+	rawVals, err := mergeValues(u.valueFiles, u.values)
 	if err != nil {
 		return err
 	}
 
-	_, err = u.client.UpdateRelease(u.release, chartPath, helm.UpdateValueOverrides(rawVals), helm.UpgradeDryRun(u.dryRun), helm.UpgradeDisableHooks(u.disableHooks))
+	labels, err := parseLabels(u.labels)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.client.UpdateRelease(
+		u.release,
+		chartPath,
+		helm.UpdateValueOverrides(rawVals),
+		helm.UpgradeDryRun(u.dryRun),
+		helm.UpgradeDisableHooks(u.disableHooks),
+		helm.UpgradeDescription(u.description),
+		helm.UpgradeLabels(labels))
 	if err != nil {
 		return fmt.Errorf("UPGRADE FAILED: %v", prettyError(err))
 	}
@@ -148,28 +167,3 @@ func (u *upgradeCmd) run() error {
 
 	return nil
 }
-
-func (u *upgradeCmd) vals() ([]byte, error) {
-	var buffer bytes.Buffer
-
-	// User specified a values file via -f/--values
-	if u.valuesFile != "" {
-		bytes, err := ioutil.ReadFile(u.valuesFile)
-		if err != nil {
-			return []byte{}, err
-		}
-		buffer.Write(bytes)
-	}
-
-	// User specified value pairs via --set
-	// These override any values in the specified file
-	if len(u.values.pairs) > 0 {
-		bytes, err := u.values.yaml()
-		if err != nil {
-			return []byte{}, err
-		}
-		buffer.Write(bytes)
-	}
-
-	return buffer.Bytes(), nil
-}
@@ -0,0 +1,147 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/strvals"
+)
+
+// values represents the command-line value pairs
+type values struct {
+	pairs map[string]interface{}
+}
+
+func (v *values) yaml() ([]byte, error) {
+	return yaml.Marshal(v.pairs)
+}
+
+func (v *values) String() string {
+	if len(v.pairs) == 0 {
+		return ""
+	}
+	out, _ := v.yaml()
+	return string(out)
+}
+
+func (v *values) Type() string {
+	// Added to pflags.Value interface, but not documented there.
+	return "struct"
+}
+
+// Set parses a line of values passed with --set into the pairs.
+func (v *values) Set(data string) error {
+	return v.add(data, strvals.ParseInto)
+}
+
+// add parses a line of values into the pairs with parse.
+func (v *values) add(data string, parse func(string, map[string]interface{}) error) error {
+	if v.pairs == nil {
+		v.pairs = map[string]interface{}{}
+	}
+	return parse(data, v.pairs)
+}
+
+// valueParser is a flag that parses its values into the pairs of values with
+// its own parser. Sharing the pairs applies --set, --set-string and
+// --set-file in the order they are given.
+type valueParser struct {
+	*values
+	parse func(string, map[string]interface{}) error
+}
+
+func (p valueParser) Set(data string) error {
+	return p.add(data, p.parse)
+}
+
+// addSetFlags adds the flags that set values on the command line to f.
+func addSetFlags(f *pflag.FlagSet, v *values) {
+	f.Var(v, "set", "set values on the command line. Separate values with commas: key1=val1,key2=val2")
+	f.Var(valueParser{v, strvals.ParseIntoString}, "set-string", "set STRING values on the command line, like --set but without inferring booleans, nulls and integers")
+	f.Var(valueParser{v, strvals.ParseIntoFile}, "set-file", "set values from files on the command line. Each value is the path of a file whose contents it is set to: key1=path1,key2=path2")
+}
+
+// valueFiles is a list of values files, given by repeating a flag.
+type valueFiles []string
+
+func (v *valueFiles) String() string {
+	return fmt.Sprint(*v)
+}
+
+func (v *valueFiles) Type() string {
+	return "valueFiles"
+}
+
+// Set adds a values file to the list.
+func (v *valueFiles) Set(value string) error {
+	*v = append(*v, value)
+	return nil
+}
+
+// mergeValues returns the values to override a chart's defaults with: the
+// contents of the values files, each merged over the ones before it, with the
+// values set on the command line merged over them all.
+func mergeValues(files valueFiles, v *values) ([]byte, error) {
+	base := map[string]interface{}{}
+	for _, file := range files {
+		data, err := readValuesFile(file)
+		if err != nil {
+			return []byte{}, err
+		}
+		layer := map[string]interface{}{}
+		if err := yaml.Unmarshal(data, &layer); err != nil {
+			return []byte{}, fmt.Errorf("failed to parse %s: %s", file, err)
+		}
+		if layer == nil {
+			continue
+		}
+		base = chartutil.CoalesceTables(layer, base)
+	}
+
+	if len(v.pairs) != 0 {
+		base = chartutil.CoalesceTables(v.pairs, base)
+	}
+	if len(base) == 0 {
+		return []byte{}, nil
+	}
+	return yaml.Marshal(base)
+}
+
+// readValuesFile reads a values file from a path or an http(s) URL.
+func readValuesFile(file string) ([]byte, error) {
+	if !strings.HasPrefix(file, "http://") && !strings.HasPrefix(file, "https://") {
+		return ioutil.ReadFile(file)
+	}
+
+	resp, err := http.Get(file)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: %s", file, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func TestSetFlags(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "motd")
+	if err := ioutil.WriteFile(file, []byte("ahoy\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	v := &values{}
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	addSetFlags(f, v)
+	args := []string{
+		"--set", "port=80,tag=1.0,debug=true",
+		"--set-string", "tag=1.0,build=007",
+		"--set-file", "motd=" + file,
+		"--set", "debug=false",
+	}
+	if err := f.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `build: "007"
+debug: false
+motd: |
+  ahoy
+port: 80
+tag: "1.0"
+`
+	if got := v.String(); got != expect {
+		t.Errorf("Expected values\n%s\ngot\n%s", expect, got)
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layers := map[string]string{
+		"base.yaml":   "name: base\nimage:\n  repo: example/app\n  tag: latest\nreplicas: 1\n",
+		"env.yaml":    "image:\n  tag: \"1.0\"\nreplicas: 3\n",
+		"region.yaml": "image:\n  registry: eu.example.com\n",
+	}
+	for name, data := range layers {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/secrets.yaml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("password: hunter2\nreplicas: 5\n"))
+	}))
+	defer srv.Close()
+
+	v := &values{}
+	if err := v.Set("image.tag=1.1,extra=1"); err != nil {
+		t.Fatal(err)
+	}
+
+	files := valueFiles{
+		filepath.Join(dir, "base.yaml"),
+		filepath.Join(dir, "env.yaml"),
+		filepath.Join(dir, "region.yaml"),
+		srv.URL + "/secrets.yaml",
+	}
+	out, err := mergeValues(files, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `extra: 1
+image:
+  registry: eu.example.com
+  repo: example/app
+  tag: "1.1"
+name: base
+password: hunter2
+replicas: 5
+`
+	if string(out) != expect {
+		t.Errorf("Expected values\n%s\ngot\n%s", expect, out)
+	}
+
+	if out, err := mergeValues(nil, &values{}); err != nil || len(out) != 0 {
+		t.Errorf("Expected no values, got %q (%v)", out, err)
+	}
+	if _, err := mergeValues(valueFiles{filepath.Join(dir, "missing.yaml")}, v); err == nil {
+		t.Error("Expected an error for a missing values file")
+	}
+	if _, err := mergeValues(valueFiles{srv.URL + "/missing.yaml"}, v); err == nil {
+		t.Error("Expected an error for a missing values URL")
+	}
+}
+
+func TestValueFilesFlag(t *testing.T) {
+	var files valueFiles
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	f.VarP(&files, "values", "f", "")
+	if err := f.Parse([]string{"-f", "a.yaml", "--values", "b,c.yaml", "-f", "http://example.com/d.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+	expect := valueFiles{"a.yaml", "b,c.yaml", "http://example.com/d.yaml"}
+	if !reflect.DeepEqual(files, expect) {
+		t.Errorf("Expected %v, got %v", expect, files)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"path"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller"
+)
+
+// auditEntry is a single line of the audit log, written as JSON.
+type auditEntry struct {
+	Operation     string `json:"operation"`
+	Release       string `json:"release"`
+	Version       int32  `json:"version,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	DryRun        bool   `json:"dryRun,omitempty"`
+	KubeUser      string `json:"kubeUser"`
+	OsUser        string `json:"osUser"`
+	Hostname      string `json:"hostname"`
+	ClientVersion string `json:"clientVersion"`
+	Error         string `json:"error,omitempty"`
+}
+
+// releaseGetter is implemented by the responses of the mutating RPCs.
+type releaseGetter interface {
+	GetRelease() *release.Release
+}
+
+// auditInterceptor writes an audit log line for each mutating RPC, recording
+// who performed which operation on which release, and whether it succeeded.
+func auditInterceptor(c ctx.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	e := auditEntry{Operation: path.Base(info.FullMethod)}
+	switch r := req.(type) {
+	case *services.InstallReleaseRequest:
+		e.Release, e.Namespace, e.DryRun = r.Name, r.Namespace, r.DryRun
+	case *services.UpdateReleaseRequest:
+		e.Release, e.DryRun = r.Name, r.DryRun
+	case *services.RollbackReleaseRequest:
+		e.Release, e.DryRun = r.Name, r.DryRun
+	case *services.UninstallReleaseRequest:
+		e.Release = r.Name
+	default:
+		return handler(c, req)
+	}
+
+	res, err := handler(c, req)
+
+	if g, ok := res.(releaseGetter); ok {
+		if rel := g.GetRelease(); rel != nil {
+			e.Release, e.Version, e.Namespace = rel.Name, rel.Version, rel.Namespace
+		}
+	}
+	id := tiller.CallerIdentity(c)
+	e.KubeUser, e.OsUser, e.Hostname, e.ClientVersion = id.KubeUser, id.OsUser, id.Hostname, id.ClientVersion
+	if err != nil {
+		e.Error = err.Error()
+	}
+	logAudit(e)
+
+	return res, err
+}
+
+func logAudit(e auditEntry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		log.Printf("warning: Failed to write audit log for %s: %s", e.Operation, err)
+		return
+	}
+	log.Printf("audit: %s", b)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func captureAudit(t *testing.T, req interface{}, method string, handler grpc.UnaryHandler) []auditEntry {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := metadata.NewContext(ctx.TODO(), metadata.Pairs("x-helm-kube-user", "alice"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/hapi.services.tiller.ReleaseService/" + method}
+	auditInterceptor(c, req, info, handler)
+
+	var entries []auditEntry
+	for _, line := range strings.Split(buf.String(), "\n") {
+		i := strings.Index(line, "audit: ")
+		if i < 0 {
+			continue
+		}
+		var e auditEntry
+		if err := json.Unmarshal([]byte(line[i+len("audit: "):]), &e); err != nil {
+			t.Fatalf("malformed audit line %q: %s", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestAuditInterceptor(t *testing.T) {
+	req := &services.UpdateReleaseRequest{Name: "angry-bird"}
+	handler := func(c ctx.Context, req interface{}) (interface{}, error) {
+		rel := &release.Release{Name: "angry-bird", Version: 2, Namespace: "default"}
+		return &services.UpdateReleaseResponse{Release: rel}, nil
+	}
+
+	entries := captureAudit(t, req, "UpdateRelease", handler)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(entries))
+	}
+	expect := auditEntry{
+		Operation: "UpdateRelease",
+		Release:   "angry-bird",
+		Version:   2,
+		Namespace: "default",
+		KubeUser:  "alice",
+	}
+	if entries[0] != expect {
+		t.Errorf("Expected %+v, got %+v", expect, entries[0])
+	}
+}
+
+func TestAuditInterceptorError(t *testing.T) {
+	req := &services.UninstallReleaseRequest{Name: "angry-bird"}
+	handler := func(c ctx.Context, req interface{}) (interface{}, error) {
+		return (*services.UninstallReleaseResponse)(nil), errors.New("boom")
+	}
+
+	entries := captureAudit(t, req, "UninstallRelease", handler)
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(entries))
+	}
+	if entries[0].Release != "angry-bird" || entries[0].Error != "boom" {
+		t.Errorf("Unexpected audit entry %+v", entries[0])
+	}
+}
+
+func TestAuditInterceptorSkipsReads(t *testing.T) {
+	req := &services.GetReleaseStatusRequest{Name: "angry-bird"}
+	handler := func(c ctx.Context, req interface{}) (interface{}, error) {
+		return &services.GetReleaseStatusResponse{}, nil
+	}
+
+	if entries := captureAudit(t, req, "GetReleaseStatus", handler); len(entries) != 0 {
+		t.Errorf("Expected no audit entries, got %d", len(entries))
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+var (
+	rpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tiller",
+		Name:      "rpc_requests_total",
+		Help:      "Number of RPCs handled, by method.",
+	}, []string{"method"})
+
+	rpcErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tiller",
+		Name:      "rpc_errors_total",
+		Help:      "Number of RPCs that returned an error, by method and gRPC code.",
+	}, []string{"method", "code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "rpc_duration_seconds",
+		Help:      "Latency of RPCs, by method.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"method"})
+
+	hookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "hook_duration_seconds",
+		Help:      "Duration of hook executions, by hook and outcome.",
+		Buckets:   []float64{.1, .5, 1, 5, 10, 30, 60, 120, 300},
+	}, []string{"hook", "outcome"})
+
+	storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Latency of storage driver operations, by driver and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"driver", "operation"})
+
+	releasesDesc = prometheus.NewDesc(
+		"tiller_releases",
+		"Number of release revisions in storage, by status.",
+		[]string{"status"}, nil,
+	)
+
+	releasesErrorsDesc = prometheus.NewDesc(
+		"tiller_releases_errors_total",
+		"Number of times the releases in storage could not be counted.",
+		nil, nil,
+	)
+
+	releases = &releasesCollector{ttl: 30 * time.Second}
+)
+
+func init() {
+	collectors := []prometheus.Collector{
+		rpcRequests,
+		rpcErrors,
+		rpcDuration,
+		hookDuration,
+		storageDuration,
+		releases,
+	}
+	for _, c := range collectors {
+		prometheus.MustRegister(c)
+	}
+}
+
+// observeRPC records the outcome of an RPC that started at start.
+func observeRPC(method string, start time.Time, err error) {
+	rpcRequests.WithLabelValues(method).Inc()
+	rpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		rpcErrors.WithLabelValues(method, grpc.Code(err).String()).Inc()
+	}
+}
+
+// metricsUnaryInterceptor records request counts, errors and latencies of unary RPCs.
+func metricsUnaryInterceptor(c ctx.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(c, req)
+	observeRPC(info.FullMethod, start, err)
+	return res, err
+}
+
+// metricsStreamInterceptor records request counts, errors and latencies of streaming RPCs.
+func metricsStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	observeRPC(info.FullMethod, start, err)
+	return err
+}
+
+// chainUnaryInterceptors combines interceptors into one, which calls them in
+// the order given. gRPC only accepts a single unary interceptor per server.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(c ctx.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chain := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chain
+			chain = func(c ctx.Context, req interface{}) (interface{}, error) {
+				return interceptor(c, req, info, next)
+			}
+		}
+		return chain(c, req)
+	}
+}
+
+// observeHook records the duration and outcome of a hook that started at start.
+func observeHook(hook string, start time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "failure"
+	}
+	hookDuration.WithLabelValues(hook, outcome).Observe(time.Since(start).Seconds())
+}
+
+// releasesCollector reports the number of releases in storage by status.
+// Listing the releases fetches and decodes every one of them, so the counts
+// are cached for ttl rather than listed on each scrape.
+type releasesCollector struct {
+	ttl time.Duration
+
+	mu sync.Mutex
+	// counts are the counts listed at updated, or nil if they are not known.
+	counts  map[string]int
+	updated time.Time
+	// errors is the number of failed listings.
+	errors int
+}
+
+func (c *releasesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- releasesDesc
+	ch <- releasesErrorsDesc
+}
+
+func (c *releasesCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts == nil || time.Since(c.updated) >= c.ttl {
+		counts, err := countReleases()
+		if err != nil {
+			log.Printf("Cannot count releases for metrics: %s", err)
+			c.errors++
+		}
+		c.counts, c.updated = counts, time.Now()
+	}
+	ch <- prometheus.MustNewConstMetric(releasesErrorsDesc, prometheus.CounterValue, float64(c.errors))
+	for status, n := range c.counts {
+		ch <- prometheus.MustNewConstMetric(releasesDesc, prometheus.GaugeValue, float64(n), status)
+	}
+}
+
+// countReleases returns the number of releases in storage by status.
+func countReleases() (map[string]int, error) {
+	if env.Releases == nil {
+		return nil, nil
+	}
+	// Counting the releases is not a storage operation worth measuring.
+	rels, err := uninstrumented(env.Releases.Driver).List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+	counts := map[string]int{}
+	for _, code := range rspb.Status_Code_name {
+		counts[code] = 0
+	}
+	for _, r := range rels {
+		if r.Info != nil && r.Info.Status != nil {
+			counts[r.Info.Status.Code.String()]++
+		}
+	}
+	return counts, nil
+}
+
+// instrumentedDriver wraps a storage driver, recording the latency of each
+// of its operations.
+type instrumentedDriver struct {
+	driver.Driver
+}
+
+func newInstrumentedDriver(d driver.Driver) driver.Driver {
+	return &instrumentedDriver{Driver: d}
+}
+
+// uninstrumented returns the driver that d wraps, if d is instrumented, so
+// that operations on it are not measured.
+func uninstrumented(d driver.Driver) driver.Driver {
+	if i, ok := d.(*instrumentedDriver); ok {
+		return i.Driver
+	}
+	return d
+}
+
+func (d *instrumentedDriver) observe(operation string, start time.Time) {
+	storageDuration.WithLabelValues(d.Name(), operation).Observe(time.Since(start).Seconds())
+}
+
+func (d *instrumentedDriver) Get(key string) (*rspb.Release, error) {
+	defer d.observe("get", time.Now())
+	return d.Driver.Get(key)
+}
+
+func (d *instrumentedDriver) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	defer d.observe("list", time.Now())
+	return d.Driver.List(filter)
+}
+
+func (d *instrumentedDriver) Query(labels map[string]string) ([]*rspb.Release, error) {
+	defer d.observe("query", time.Now())
+	return d.Driver.Query(labels)
+}
+
+func (d *instrumentedDriver) Create(key string, rls *rspb.Release) error {
+	defer d.observe("create", time.Now())
+	return d.Driver.Create(key, rls)
+}
+
+func (d *instrumentedDriver) Update(key string, rls *rspb.Release) error {
+	defer d.observe("update", time.Now())
+	return d.Driver.Update(key, rls)
+}
+
+func (d *instrumentedDriver) Delete(key string) (*rspb.Release, error) {
+	defer d.observe("delete", time.Now())
+	return d.Driver.Delete(key)
+}
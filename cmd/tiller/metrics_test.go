@@ -0,0 +1,182 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func scrape(t *testing.T) string {
+	srv := httptest.NewServer(newProbesMux())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics returned an error (%s)", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /metrics returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestMetrics(t *testing.T) {
+	defer func(s *storage.Storage) { env.Releases = s }(env.Releases)
+	env.Releases = storage.Init(newInstrumentedDriver(driver.NewMemory()))
+	env.Releases.Create(releaseStub())
+	releases.counts = nil
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/hapi.services.tiller.ReleaseService/UpdateRelease"}
+	failing := func(c ctx.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("upgrade failed")
+	}
+	metricsUnaryInterceptor(ctx.TODO(), nil, info, failing)
+
+	out := scrape(t)
+	expect := []string{
+		`tiller_rpc_requests_total{method="/hapi.services.tiller.ReleaseService/UpdateRelease"}`,
+		`tiller_rpc_errors_total{code="Unknown",method="/hapi.services.tiller.ReleaseService/UpdateRelease"}`,
+		`tiller_rpc_duration_seconds_count{method="/hapi.services.tiller.ReleaseService/UpdateRelease"}`,
+		`tiller_storage_operation_duration_seconds_count{driver="Memory",operation="create"}`,
+		`tiller_releases{status="DEPLOYED"} 1`,
+		`tiller_releases{status="FAILED"} 0`,
+	}
+	for _, e := range expect {
+		if !strings.Contains(out, e) {
+			t.Errorf("expected metrics to contain %s", e)
+		}
+	}
+}
+
+// countingDriver is a storage driver that counts its listings.
+type countingDriver struct {
+	driver.Driver
+	lists int
+}
+
+func (d *countingDriver) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	d.lists++
+	return d.Driver.List(filter)
+}
+
+func collect(c prometheus.Collector) []prometheus.Metric {
+	ch := make(chan prometheus.Metric, 100)
+	c.Collect(ch)
+	close(ch)
+	var metrics []prometheus.Metric
+	for m := range ch {
+		metrics = append(metrics, m)
+	}
+	return metrics
+}
+
+func TestReleasesCollector(t *testing.T) {
+	defer func(s *storage.Storage) { env.Releases = s }(env.Releases)
+	d := &countingDriver{Driver: driver.NewMemory()}
+	env.Releases = storage.Init(newInstrumentedDriver(d))
+	env.Releases.Create(releaseStub())
+
+	c := &releasesCollector{ttl: time.Hour}
+	n := len(collect(c))
+	if n != len(rspb.Status_Code_name)+1 {
+		t.Errorf("expected a gauge per status and an error counter, got %d metrics", n)
+	}
+	collect(c)
+	if d.lists != 1 {
+		t.Errorf("expected the releases to be listed once, got %d", d.lists)
+	}
+
+	// Failures are counted, and the gauges are left out until the next listing.
+	env.Releases = storage.Init(failingDriver{driver.NewMemory()})
+	c = &releasesCollector{ttl: 0}
+	for i := 0; i < 2; i++ {
+		metrics := collect(c)
+		if len(metrics) != 1 {
+			t.Fatalf("expected only the error counter, got %d metrics", len(metrics))
+		}
+		var m dto.Metric
+		if err := metrics[0].Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		if v := m.GetCounter().GetValue(); v != float64(i+1) {
+			t.Errorf("expected %d errors, got %v", i+1, v)
+		}
+	}
+}
+
+func TestReadinessNotInstrumented(t *testing.T) {
+	defer func(s *storage.Storage) { env.Releases = s }(env.Releases)
+	env.Releases = storage.Init(newInstrumentedDriver(driver.NewMemory()))
+
+	lists := func() uint64 {
+		var m dto.Metric
+		if err := storageDuration.WithLabelValues("Memory", "list").Write(&m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+	before := lists()
+	readinessError()
+	if after := lists(); after != before {
+		t.Errorf("expected the readiness check not to be measured, got %d listings", after-before)
+	}
+}
+
+func TestChainUnaryInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(c ctx.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(c, req)
+		}
+	}
+	handler := func(c ctx.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return req, nil
+	}
+
+	chain := chainUnaryInterceptors(interceptor("first"), interceptor("second"))
+	res, err := chain(ctx.TODO(), "request", &grpc.UnaryServerInfo{}, handler)
+	if err != nil || res != "request" {
+		t.Fatalf("unexpected result %v, %v", res, err)
+	}
+	expect := []string{"first", "second", "handler"}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Errorf("expected calls %v, got %v", expect, calls)
+	}
+}
@@ -17,20 +17,215 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// healthServer is the grpc.health.v1 service registered on rootServer. The
+// status of the release service follows the result of the readiness check.
+var healthServer = health.NewServer()
+
+// releaseServiceName is the name the release service is reported under by
+// healthServer.
+const releaseServiceName = "hapi.services.tiller.ReleaseService"
+
+// probeTimeout is the maximum time a single check may take.
+const probeTimeout = 5 * time.Second
+
+var (
+	// livenessAddr is the address of rootServer's listener, as dialed by the
+	// liveness check. It is set by start() once rootServer is listening.
+	livenessAddr string
+	// livenessCreds are the credentials the liveness check dials with, or nil
+	// if TLS is disabled.
+	livenessCreds credentials.TransportCredentials
 )
 
-func readinessProbe(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+var (
+	// readiness checks that the storage driver and the Kubernetes API can be
+	// reached. Its result is cached so probes do not overload either.
+	readiness = &cachedCheck{check: checkReadiness, ttl: 10 * time.Second, timeout: probeTimeout}
+	// liveness checks that the health service still answers requests.
+	liveness = &cachedCheck{check: checkLiveness, timeout: probeTimeout}
+)
+
+// cachedCheck runs a check with a timeout, and reuses its result for ttl.
+type cachedCheck struct {
+	check   func() error
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu      sync.Mutex
+	checked time.Time
+	err     error
+}
+
+// Run returns the result of the check, running it if the cached result has
+// expired.
+func (c *cachedCheck) Run() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checked.IsZero() && time.Since(c.checked) < c.ttl {
+		return c.err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- c.check() }()
+	select {
+	case c.err = <-done:
+	case <-time.After(c.timeout):
+		c.err = fmt.Errorf("check timed out after %s", c.timeout)
+	}
+	c.checked = time.Now()
+	return c.err
+}
+
+// Reset discards the cached result, so that the next Run runs the check.
+func (c *cachedCheck) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checked = time.Time{}
+}
+
+func checkReadiness() error {
+	err := readinessError()
+	status := healthpb.HealthCheckResponse_SERVING
+	if err != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	healthServer.SetServingStatus(releaseServiceName, status)
+	return err
+}
+
+func readinessError() error {
+	if isDraining() {
+		return errors.New("tiller is shutting down")
+	}
+	// The probe's listings are not storage operations worth measuring.
+	if _, err := uninstrumented(env.Releases.Driver).List(func(*rspb.Release) bool { return false }); err != nil {
+		return fmt.Errorf("cannot list releases: %s", err)
+	}
+	cli, err := env.KubeClient.APIClient()
+	if err != nil {
+		return fmt.Errorf("cannot create Kubernetes client: %s", err)
+	}
+	if _, err := cli.Discovery().ServerGroups(); err != nil {
+		return fmt.Errorf("cannot reach Kubernetes API: %s", err)
+	}
+	return nil
+}
+
+// checkLiveness calls the health service through rootServer's listener, the
+// way a client would. It fails if the server has stopped, or is wedged badly
+// enough that it cannot accept a connection and answer in time.
+func checkLiveness() error {
+	c, cancel := ctx.WithTimeout(ctx.Background(), probeTimeout)
+	defer cancel()
+
+	// The dial does not block, so that a server that refuses connections
+	// fails the call at once rather than at the deadline.
+	opt := grpc.WithInsecure()
+	if livenessCreds != nil {
+		opt = grpc.WithTransportCredentials(livenessCreds)
+	}
+	conn, err := grpc.DialContext(c, livenessAddr, opt)
+	if err != nil {
+		return fmt.Errorf("cannot connect to %s: %s", livenessAddr, err)
+	}
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(c, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+// loopbackAddr returns the address to dial to reach a listener on a, using
+// the loopback interface if a is a wildcard address.
+func loopbackAddr(a net.Addr) string {
+	host, port, err := net.SplitHostPort(a.String())
+	if err != nil {
+		return a.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// livenessCredentials returns the credentials the liveness check dials a
+// server using server with, or nil if server is nil.
+//
+// The check dials Tiller itself, so the server certificate is not verified.
+// If clients must present a certificate, Tiller presents its own, which must
+// then be signed by the CA and valid for client authentication.
+func livenessCredentials(server *tls.Config) (credentials.TransportCredentials, error) {
+	if server == nil {
+		return nil, nil
+	}
+	cfg := &tls.Config{InsecureSkipVerify: true}
+	if server.ClientAuth == tls.RequireAndVerifyClientCert {
+		cert, err := x509.ParseCertificate(server.Certificates[0].Certificate[0])
+		if err != nil {
+			return nil, err
+		}
+		opts := x509.VerifyOptions{
+			Roots:     server.ClientCAs,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		}
+		if _, err := cert.Verify(opts); err != nil {
+			return nil, fmt.Errorf("the liveness check presents Tiller's certificate as a client certificate, but it would be rejected: %s", err)
+		}
+		cfg.Certificates = server.Certificates
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// watchReadiness runs the readiness check every interval until stop is
+// closed, so that the status reported by healthServer follows the state of
+// Tiller even when nothing calls the readiness probe.
+func watchReadiness(c *cachedCheck, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.Run()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
 }
 
-func livenessProbe(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+func probeHandler(name string, c *cachedCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := c.Run(); err != nil {
+			log.Printf("warning: %s check failed: %s", name, err)
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
 }
 
 func newProbesMux() *http.ServeMux {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/readiness", readinessProbe)
-	mux.HandleFunc("/liveness", livenessProbe)
+	mux.HandleFunc("/readiness", probeHandler("readiness", readiness))
+	mux.HandleFunc("/liveness", probeHandler("liveness", liveness))
+	mux.Handle("/metrics", prometheus.Handler())
 	return mux
 }
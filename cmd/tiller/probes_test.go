@@ -17,28 +17,276 @@ limitations under the License.
 package main
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
-func TestProbesServer(t *testing.T) {
-	mux := newProbesMux()
-	srv := httptest.NewServer(mux)
-	defer srv.Close()
-	resp, err := http.Get(srv.URL + "/readiness")
+// withProbeEnv runs fn with a mock environment and fresh probe checks.
+func withProbeEnv(t *testing.T, fn func(e *environment.Environment)) {
+	origEnv, origReadiness, origLiveness := env, readiness, liveness
+	origAddr, origCreds := livenessAddr, livenessCreds
+	defer func() {
+		env, readiness, liveness = origEnv, origReadiness, origLiveness
+		livenessAddr, livenessCreds = origAddr, origCreds
+	}()
+
+	env = mockEnvironment()
+	readiness = &cachedCheck{check: checkReadiness, ttl: time.Minute, timeout: probeTimeout}
+	liveness = &cachedCheck{check: checkLiveness, timeout: probeTimeout}
+	fn(env)
+}
+
+// serveHealth serves healthServer on a loopback listener, with TLS if
+// tlsConfig is not nil, and points the liveness check at it.
+func serveHealth(t *testing.T, tlsConfig *tls.Config) *grpc.Server {
+	creds, err := livenessCredentials(tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lstn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer(serverOptions(tlsConfig)...)
+	healthpb.RegisterHealthServer(s, healthServer)
+	go s.Serve(lstn)
+	livenessAddr, livenessCreds = loopbackAddr(lstn.Addr()), creds
+	return s
+}
+
+// testServerTLS returns the TLS configuration of a server requiring client
+// certificates, whose own certificate has the given extended key usages.
+func testServerTLS(t *testing.T, usages ...x509.ExtKeyUsage) *tls.Config {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "tiller-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &key.PublicKey, key)
 	if err != nil {
-		t.Fatalf("GET /readiness returned an error (%s)", err)
+		t.Fatal(err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("GET /readiness returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "tiller"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  usages,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
 	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+}
 
-	resp, err = http.Get(srv.URL + "/liveness")
+func getStatus(t *testing.T, url string) int {
+	resp, err := http.Get(url)
 	if err != nil {
-		t.Fatalf("GET /liveness returned an error (%s)", err)
+		t.Fatalf("GET %s returned an error (%s)", url, err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		t.Fatalf("GET /liveness returned status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestProbesServer(t *testing.T) {
+	withProbeEnv(t, func(e *environment.Environment) {
+		defer serveHealth(t, nil).Stop()
+		mux := newProbesMux()
+		srv := httptest.NewServer(mux)
+		defer srv.Close()
+
+		if code := getStatus(t, srv.URL+"/readiness"); code != http.StatusOK {
+			t.Fatalf("GET /readiness returned status code %d, expected %d", code, http.StatusOK)
+		}
+		if code := getStatus(t, srv.URL+"/liveness"); code != http.StatusOK {
+			t.Fatalf("GET /liveness returned status code %d, expected %d", code, http.StatusOK)
+		}
+	})
+}
+
+// failingDriver is a storage driver whose listings always fail.
+type failingDriver struct {
+	driver.Driver
+}
+
+func (failingDriver) List(func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	return nil, errors.New("storage unreachable")
+}
+
+func TestReadinessStorageFailure(t *testing.T) {
+	withProbeEnv(t, func(e *environment.Environment) {
+		e.Releases = storage.Init(failingDriver{driver.NewMemory()})
+
+		srv := httptest.NewServer(newProbesMux())
+		defer srv.Close()
+
+		if code := getStatus(t, srv.URL+"/readiness"); code != http.StatusServiceUnavailable {
+			t.Errorf("GET /readiness returned status code %d, expected %d", code, http.StatusServiceUnavailable)
+		}
+		res, err := healthServer.Check(nil, &healthpb.HealthCheckRequest{Service: releaseServiceName})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Status != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("expected health status NOT_SERVING, got %s", res.Status)
+		}
+	})
+}
+
+func TestCachedCheck(t *testing.T) {
+	calls := 0
+	c := &cachedCheck{
+		check:   func() error { calls++; return nil },
+		ttl:     time.Minute,
+		timeout: time.Second,
+	}
+	c.Run()
+	c.Run()
+	if calls != 1 {
+		t.Errorf("expected the check to run once, ran %d times", calls)
+	}
+
+	c = &cachedCheck{
+		check:   func() error { time.Sleep(time.Second); return nil },
+		timeout: 10 * time.Millisecond,
+	}
+	if err := c.Run(); err == nil {
+		t.Error("expected the check to time out")
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	withProbeEnv(t, func(e *environment.Environment) {
+		s := serveHealth(t, nil)
+		if err := liveness.Run(); err != nil {
+			t.Errorf("expected a live server, got %s", err)
+		}
+		s.Stop()
+		if err := liveness.Run(); err == nil {
+			t.Error("expected a stopped server not to be live")
+		}
+	})
+}
+
+func TestLivenessMutualTLS(t *testing.T) {
+	withProbeEnv(t, func(e *environment.Environment) {
+		cfg := testServerTLS(t, x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth)
+		defer serveHealth(t, cfg).Stop()
+		if err := liveness.Run(); err != nil {
+			t.Errorf("expected a live server, got %s", err)
+		}
+	})
+}
+
+func TestLivenessCredentials(t *testing.T) {
+	if creds, err := livenessCredentials(nil); creds != nil || err != nil {
+		t.Errorf("expected no credentials without TLS, got %v (%v)", creds, err)
+	}
+	// Tiller would reject its own certificate as a client certificate.
+	cfg := testServerTLS(t, x509.ExtKeyUsageServerAuth)
+	if _, err := livenessCredentials(cfg); err == nil {
+		t.Error("expected a server-only certificate to be refused")
+	}
+	cfg.ClientAuth = tls.NoClientCert
+	if _, err := livenessCredentials(cfg); err != nil {
+		t.Errorf("expected the certificate not to be needed without client verification, got %s", err)
+	}
+}
+
+func TestLoopbackAddr(t *testing.T) {
+	tests := []struct {
+		addr, expect string
+	}{
+		{"[::]:44134", "127.0.0.1:44134"},
+		{"0.0.0.0:44134", "127.0.0.1:44134"},
+		{"10.0.0.1:44134", "10.0.0.1:44134"},
+	}
+	for _, tt := range tests {
+		a, err := net.ResolveTCPAddr("tcp", tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := loopbackAddr(a); got != tt.expect {
+			t.Errorf("loopbackAddr(%s) = %s, expected %s", tt.addr, got, tt.expect)
+		}
+	}
+}
+
+func TestWatchReadiness(t *testing.T) {
+	withProbeEnv(t, func(e *environment.Environment) {
+		e.Releases = storage.Init(failingDriver{driver.NewMemory()})
+		check := &cachedCheck{check: checkReadiness, ttl: 10 * time.Millisecond, timeout: probeTimeout}
+
+		stop := make(chan struct{})
+		done := make(chan struct{})
+		go func() {
+			watchReadiness(check, 10*time.Millisecond, stop)
+			close(done)
+		}()
+		defer func() {
+			close(stop)
+			<-done
+		}()
+
+		// The status must follow the storage without any call to /readiness.
+		waitForStatus(t, healthpb.HealthCheckResponse_NOT_SERVING)
+		check.mu.Lock()
+		e.Releases = storage.Init(driver.NewMemory())
+		check.mu.Unlock()
+		waitForStatus(t, healthpb.HealthCheckResponse_SERVING)
+	})
+}
+
+// waitForStatus waits for healthServer to report status for the release
+// service.
+func waitForStatus(t *testing.T, status healthpb.HealthCheckResponse_ServingStatus) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		res, err := healthServer.Check(nil, &healthpb.HealthCheckRequest{Service: releaseServiceName})
+		if err == nil && res.Status == status {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected health status %s, got %v (%v)", status, res, err)
+		}
+		time.Sleep(5 * time.Millisecond)
 	}
 }
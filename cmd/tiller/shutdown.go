@@ -0,0 +1,67 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/helm/pkg/tiller"
+)
+
+// drainTimeout is the time in-flight RPCs are given to complete once Tiller
+// has been asked to stop.
+var drainTimeout = 25 * time.Second
+
+// draining is set to 1 once Tiller has been asked to stop. It is accessed
+// atomically.
+var draining int32
+
+func isDraining() bool {
+	return atomic.LoadInt32(&draining) == 1
+}
+
+// shutdown stops the gRPC server gracefully.
+//
+// Readiness is failed first, so that no new clients are sent to this Tiller.
+// In-flight RPCs are then given until timeout to complete. Releases that are
+// still being operated on by rs at the deadline are recorded as INTERRUPTED,
+// and the server is stopped. It returns whether all RPCs completed in time.
+func shutdown(s *grpc.Server, rs *tiller.ReleaseServer, timeout time.Duration) bool {
+	atomic.StoreInt32(&draining, 1)
+	readiness.Reset()
+	readiness.Run()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(timeout):
+		log.Printf("warning: RPCs did not complete within %s", timeout)
+		rs.InterruptReleases()
+		s.Stop()
+		return false
+	}
+}
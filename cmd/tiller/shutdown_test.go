@@ -0,0 +1,129 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// blockingKubeClient blocks in Create until unblock is closed.
+type blockingKubeClient struct {
+	environment.PrintingKubeClient
+	started chan struct{}
+	unblock chan struct{}
+}
+
+func (b *blockingKubeClient) Create(ns string, r io.Reader) error {
+	close(b.started)
+	<-b.unblock
+	return nil
+}
+
+// withShutdownServer serves rs on a local gRPC server, and calls fn with a
+// client connected to it.
+func withShutdownServer(t *testing.T, rs *tiller.ReleaseServer, fn func(s *grpc.Server, client services.ReleaseServiceClient)) {
+	origReadiness := readiness
+	defer func() {
+		readiness = origReadiness
+		atomic.StoreInt32(&draining, 0)
+	}()
+	readiness = &cachedCheck{check: checkReadiness, ttl: time.Minute, timeout: probeTimeout}
+
+	lstn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	services.RegisterReleaseServiceServer(s, rs)
+	go s.Serve(lstn)
+
+	conn, err := grpc.Dial(lstn.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	fn(s, services.NewReleaseServiceClient(conn))
+}
+
+func TestShutdownDrains(t *testing.T) {
+	e := mockEnvironment()
+	rs := tiller.NewReleaseServer(e)
+	withShutdownServer(t, rs, func(s *grpc.Server, client services.ReleaseServiceClient) {
+		req := &services.InstallReleaseRequest{Chart: chartStub(), Name: "drained", Namespace: "default"}
+		if _, err := client.InstallRelease(helm.NewContext(), req); err != nil {
+			t.Fatalf("Failed install: %s", err)
+		}
+
+		if !shutdown(s, rs, 5*time.Second) {
+			t.Error("Expected the server to drain")
+		}
+		if err := readiness.Run(); err == nil {
+			t.Error("Expected readiness to fail once shutting down")
+		}
+		rel, err := e.Releases.Get("drained", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel.Info.Status.Code != release.Status_DEPLOYED {
+			t.Errorf("Expected release to be DEPLOYED, got %s", rel.Info.Status.Code)
+		}
+	})
+}
+
+func TestShutdownInterrupts(t *testing.T) {
+	e := mockEnvironment()
+	kube := &blockingKubeClient{started: make(chan struct{}), unblock: make(chan struct{})}
+	e.KubeClient = kube
+	rs := tiller.NewReleaseServer(e)
+	defer close(kube.unblock)
+
+	withShutdownServer(t, rs, func(s *grpc.Server, client services.ReleaseServiceClient) {
+		errCh := make(chan error, 1)
+		go func() {
+			req := &services.InstallReleaseRequest{Chart: chartStub(), Name: "interrupted", Namespace: "default"}
+			_, err := client.InstallRelease(helm.NewContext(), req)
+			errCh <- err
+		}()
+		<-kube.started
+
+		if shutdown(s, rs, 100*time.Millisecond) {
+			t.Error("Expected the drain to time out")
+		}
+		if err := <-errCh; err == nil {
+			t.Error("Expected the install to fail when the server stopped")
+		}
+		rel, err := e.Releases.Get("interrupted", 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rel.Info.Status.Code != release.Status_INTERRUPTED {
+			t.Errorf("Expected release to be INTERRUPTED, got %s", rel.Info.Status.Code)
+		}
+	})
+}
@@ -17,17 +17,28 @@ limitations under the License.
 package main // import "k8s.io/helm/cmd/tiller"
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
-	"k8s.io/helm/cmd/tiller/environment"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/tlsutil"
 )
 
 const (
@@ -37,18 +48,35 @@ const (
 
 // rootServer is the root gRPC server.
 //
-// Each gRPC service registers itself to this server during init().
-var rootServer = grpc.NewServer()
+// It is created by start() once the TLS flags have been parsed. Mutating RPCs
+// are recorded in the audit log by auditInterceptor, and all RPCs are measured
+// by the metrics interceptors.
+var rootServer *grpc.Server
 
 // env is the default environment.
 //
 // Any changes to env should be done before rootServer.Serve() is called.
 var env = environment.New()
 
+// srv is the release server registered on rootServer.
+var srv = tiller.NewReleaseServer(env)
+
+// gotpl is the Go template engine of env.
+var gotpl = env.EngineYard[environment.GoTplEngine].(*engine.Engine)
+
 var (
 	addr  = ":44134"
 	probe = ":44135"
 	store = storageConfigMap
+
+	tlsEnable     bool
+	tlsVerify     bool
+	tlsCertFile   = "/etc/certs/tls.crt"
+	tlsKeyFile    = "/etc/certs/tls.key"
+	tlsCaCertFile = "/etc/certs/ca.crt"
+
+	templateCacheSize = 256
+	enginesDir        string
 )
 
 const globalUsage = `The Kubernetes Helm server.
@@ -69,30 +97,89 @@ func main() {
 	pf := rootCommand.PersistentFlags()
 	pf.StringVarP(&addr, "listen", "l", ":44134", "The address:port to listen on")
 	pf.StringVar(&store, "storage", storageConfigMap, "The storage driver to use. One of 'configmap' or 'memory'")
+	pf.StringSliceVar(&env.AllowedNamespaces, "allowed-namespaces", []string{}, "The namespaces releases may be installed into. If empty, all namespaces are allowed")
+	pf.BoolVar(&tlsEnable, "tls", false, "Enable TLS")
+	pf.BoolVar(&tlsVerify, "tls-verify", false, "Enable TLS and require clients to present a certificate signed by the CA. The liveness probe presents the TLS certificate, so it must be valid for client authentication too. Implies --tls")
+	pf.StringVar(&tlsCertFile, "tls-cert", tlsCertFile, "The path to the TLS certificate file")
+	pf.StringVar(&tlsKeyFile, "tls-key", tlsKeyFile, "The path to the TLS key file")
+	pf.StringVar(&tlsCaCertFile, "tls-ca-cert", tlsCaCertFile, "The path to the CA certificate used to verify clients")
+	pf.DurationVar(&drainTimeout, "drain-timeout", drainTimeout, "The time in-flight requests are given to complete on SIGTERM or SIGINT, after which their releases are marked INTERRUPTED")
+
+	// Charts are rendered in-process, so bound what a single render may use.
+	pf.DurationVar(&gotpl.MaxRenderTime, "max-render-time", time.Minute, "The time a chart may take to render. 0 for no limit")
+	pf.IntVar(&gotpl.MaxIncludeDepth, "max-include-depth", 100, "How deeply include and tpl calls may nest when rendering. 0 for no limit")
+	pf.IntVar(&gotpl.MaxOutputSize, "max-output-size", 10<<20, "The size in bytes a chart's rendered templates may reach, and separately the memory its template functions may allocate. 0 for no limit")
+	pf.StringVar(&enginesDir, "engines-dir", "", "A directory of executables to register as template engines, each named after its file. Each is given --max-render-time to render a chart")
+	pf.IntVar(&templateCacheSize, "template-cache-size", templateCacheSize, "The number of charts whose parsed templates are kept for reuse. Subcharts count as charts. 0 to disable")
 	rootCommand.Execute()
 }
 
 func start(c *cobra.Command, args []string) {
 	switch store {
 	case storageMemory:
-		env.Releases = storage.Init(driver.NewMemory())
+		env.Releases = storage.Init(newInstrumentedDriver(driver.NewMemory()))
 	case storageConfigMap:
 		c, err := env.KubeClient.APIClient()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Cannot initialize Kubernetes connection: %s", err)
 		}
-		env.Releases = storage.Init(driver.NewConfigMaps(c.ConfigMaps(environment.TillerNamespace)))
+		env.Releases = storage.Init(newInstrumentedDriver(driver.NewConfigMaps(c.ConfigMaps(env.Namespace))))
 	}
 
+	if templateCacheSize > 0 {
+		gotpl.Cache = engine.NewCache(templateCacheSize)
+	}
+	if enginesDir != "" {
+		engines, err := engine.LoadExecEngines(enginesDir, gotpl.MaxRenderTime, gotpl.MaxOutputSize)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot load template engines: %s\n", err)
+			os.Exit(1)
+		}
+		for name, e := range engines {
+			if _, ok := env.EngineYard[name]; ok {
+				fmt.Fprintf(os.Stderr, "Cannot register template engine %s: the name is taken\n", name)
+				os.Exit(1)
+			}
+			env.EngineYard[name] = e
+			fmt.Printf("Registered template engine %s (%s)\n", name, e.Path)
+		}
+	}
+
+	tlsConfig, err := serverTLSConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not create server: %s\n", err)
+		os.Exit(1)
+	}
+	livenessCreds, err = livenessCredentials(tlsConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not create server: %s\n", err)
+		os.Exit(1)
+	}
+	rootServer = grpc.NewServer(serverOptions(tlsConfig)...)
+	srv.HookObserver = observeHook
+	services.RegisterReleaseServiceServer(rootServer, srv)
+	healthpb.RegisterHealthServer(rootServer, healthServer)
+
 	lstn, err := net.Listen("tcp", addr)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Server died: %s\n", err)
 		os.Exit(1)
 	}
+	livenessAddr = loopbackAddr(lstn.Addr())
 
 	fmt.Printf("Tiller is running on %s\n", addr)
 	fmt.Printf("Tiller probes server is running on %s\n", probe)
 	fmt.Printf("Storage driver is %s\n", env.Releases.Name())
+	fmt.Printf("Tiller namespace is %s\n", env.Namespace)
+	if len(env.AllowedNamespaces) > 0 {
+		fmt.Printf("Allowed namespaces are %s\n", strings.Join(env.AllowedNamespaces, ", "))
+	}
+	if tlsEnable || tlsVerify {
+		fmt.Printf("TLS is enabled (client verification: %t)\n", tlsVerify)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
 
 	srvErrCh := make(chan error)
 	probeErrCh := make(chan error)
@@ -102,6 +189,10 @@ func start(c *cobra.Command, args []string) {
 		}
 	}()
 
+	// Keep the status of the release service in the health service current,
+	// whether or not the readiness probe is being called.
+	go watchReadiness(readiness, readiness.ttl, nil)
+
 	go func() {
 		mux := newProbesMux()
 		if err := http.ListenAndServe(probe, mux); err != nil {
@@ -115,5 +206,37 @@ func start(c *cobra.Command, args []string) {
 		os.Exit(1)
 	case err := <-probeErrCh:
 		fmt.Fprintf(os.Stderr, "Probes server died: %s\n", err)
+	case sig := <-sigCh:
+		fmt.Printf("Received %s, shutting down\n", sig)
+		if !shutdown(rootServer, srv, drainTimeout) {
+			fmt.Fprintf(os.Stderr, "Some releases were interrupted\n")
+		}
+	}
+}
+
+// serverOptions returns the options for the root gRPC server, adding TLS
+// credentials if tlsConfig is not nil.
+func serverOptions(tlsConfig *tls.Config) []grpc.ServerOption {
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(chainUnaryInterceptors(metricsUnaryInterceptor, auditInterceptor)),
+		grpc.StreamInterceptor(metricsStreamInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	return opts
+}
+
+// serverTLSConfig returns the TLS configuration of the root gRPC server, or
+// nil if TLS is disabled.
+func serverTLSConfig() (*tls.Config, error) {
+	if !tlsEnable && !tlsVerify {
+		return nil, nil
 	}
+	return tlsutil.ServerConfig(tlsutil.Options{
+		CertFile:   tlsCertFile,
+		KeyFile:    tlsKeyFile,
+		CaCertFile: tlsCaCertFile,
+		Verify:     tlsVerify,
+	})
 }
@@ -17,10 +17,15 @@ limitations under the License.
 package main
 
 import (
+	"os"
 	"testing"
 
-	"k8s.io/helm/cmd/tiller/environment"
 	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 // These are canary tests to make sure that the default server actually
@@ -45,3 +50,26 @@ func TestInit(t *testing.T) {
 		t.Fatalf("Template engine GoTplEngine returned nil.")
 	}
 }
+
+func mockEnvironment() *environment.Environment {
+	e := environment.New()
+	e.Releases = storage.Init(driver.NewMemory())
+	e.KubeClient = &environment.PrintingKubeClient{Out: os.Stdout}
+	return e
+}
+
+func chartStub() *chart.Chart {
+	return &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{{Name: "hello", Data: []byte("hello: world")}},
+	}
+}
+
+func releaseStub() *release.Release {
+	return &release.Release{
+		Name:    "angry-panda",
+		Version: 1,
+		Info:    &release.Info{Status: &release.Status{Code: release.Status_DEPLOYED}},
+		Chart:   chartStub(),
+	}
+}
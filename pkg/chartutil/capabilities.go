@@ -0,0 +1,75 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"k8s.io/kubernetes/pkg/version"
+
+	tversion "k8s.io/helm/pkg/proto/hapi/version"
+	hversion "k8s.io/helm/pkg/version"
+)
+
+var (
+	// DefaultVersionSet is the default version set, which includes only Core V1 ("v1").
+	DefaultVersionSet = NewVersionSet("v1")
+
+	// DefaultCapabilities is the capabilities used when charts are rendered
+	// without a cluster, for example by 'helm template' and 'helm lint'. The
+	// Kubernetes version is that of the client libraries.
+	DefaultCapabilities = &Capabilities{
+		APIVersions:   DefaultVersionSet,
+		KubeVersion:   defaultKubeVersion(),
+		TillerVersion: hversion.GetVersionProto(),
+	}
+)
+
+func defaultKubeVersion() *version.Info {
+	v := version.Get()
+	return &v
+}
+
+// Capabilities describes the capabilities of the Kubernetes cluster that Tiller is attached to.
+//
+// They are available to templates as .Capabilities.
+type Capabilities struct {
+	// APIVersions is the set of API versions the cluster serves.
+	APIVersions VersionSet
+	// KubeVersion is the version of the Kubernetes API server.
+	KubeVersion *version.Info
+	// TillerVersion is the version of Tiller.
+	TillerVersion *tversion.Version
+}
+
+// VersionSet is a set of Kubernetes API versions.
+type VersionSet map[string]struct{}
+
+// NewVersionSet creates a new version set from a list of strings.
+func NewVersionSet(apiVersions ...string) VersionSet {
+	vs := VersionSet{}
+	for _, v := range apiVersions {
+		vs[v] = struct{}{}
+	}
+	return vs
+}
+
+// Has returns true if the version string is in the set.
+//
+//	vs.Has("extensions/v1beta1")
+func (v VersionSet) Has(apiVersion string) bool {
+	_, ok := v[apiVersion]
+	return ok
+}
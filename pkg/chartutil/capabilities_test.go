@@ -0,0 +1,40 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"testing"
+)
+
+func TestVersionSet(t *testing.T) {
+	vs := NewVersionSet("v1", "extensions/v1beta1")
+	if !vs.Has("extensions/v1beta1") {
+		t.Error("Expected vs to contain extensions/v1beta1")
+	}
+	if vs.Has("Spanish/inquisition") {
+		t.Error("No one expects the Spanish/inquisition")
+	}
+}
+
+func TestDefaultCapabilities(t *testing.T) {
+	if !DefaultCapabilities.APIVersions.Has("v1") {
+		t.Error("Expected default capabilities to include v1")
+	}
+	if DefaultCapabilities.KubeVersion == nil || DefaultCapabilities.TillerVersion == nil {
+		t.Error("Expected default capabilities to have versions")
+	}
+}
@@ -16,6 +16,13 @@ limitations under the License.
 package chartutil
 
 import (
+	"encoding/base64"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
 	"github.com/golang/protobuf/ptypes/any"
 )
 
@@ -56,3 +63,89 @@ func (f Files) GetBytes(name string) []byte {
 func (f Files) Get(name string) string {
 	return string(f.GetBytes(name))
 }
+
+// Glob returns the files whose paths match pattern.
+//
+// The pattern uses the syntax of path.Match, so '*' does not match '/'.
+//
+//	{{range $path, $bytes := .Files.Glob "config/*.conf"}}
+func (f Files) Glob(pattern string) Files {
+	nf := Files{}
+	for name, contents := range f {
+		if ok, _ := path.Match(pattern, name); ok {
+			nf[name] = contents
+		}
+	}
+	return nf
+}
+
+// Lines returns the lines of the given file, without their line endings.
+//
+// A missing file has no lines.
+//
+//	{{range .Files.Lines "hosts.txt"}}
+func (f Files) Lines(name string) []string {
+	s := strings.TrimSuffix(f.Get(name), "\n")
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, "\n")
+}
+
+// AsConfig renders the files as the data of a ConfigMap, keyed by the base
+// name of each file. It fails if two files have the same base name.
+//
+//	data:
+//	{{(.Files.Glob "config/*").AsConfig | indent 2}}
+func (f Files) AsConfig() (string, error) {
+	m, err := f.byBase(func(b []byte) string { return string(b) })
+	if err != nil {
+		return "", err
+	}
+	return toData(m), nil
+}
+
+// AsSecrets renders the files as the data of a Secret, keyed by the base
+// name of each file, with base64 encoded contents. It fails if two files have
+// the same base name.
+//
+//	data:
+//	{{(.Files.Glob "secrets/*").AsSecrets | indent 2}}
+func (f Files) AsSecrets() (string, error) {
+	m, err := f.byBase(base64.StdEncoding.EncodeToString)
+	if err != nil {
+		return "", err
+	}
+	return toData(m), nil
+}
+
+// byBase returns the encoded contents of the files keyed by their base names.
+func (f Files) byBase(encode func([]byte) string) (map[string]string, error) {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m := map[string]string{}
+	paths := map[string]string{}
+	for _, name := range names {
+		key := path.Base(name)
+		if other, ok := paths[key]; ok {
+			return nil, fmt.Errorf("files %q and %q would both be stored under the key %q", other, name, key)
+		}
+		paths[key] = name
+		m[key] = encode(f[name])
+	}
+	return m, nil
+}
+
+// toData renders m as YAML, or as an empty string if m is empty.
+func toData(m map[string]string) string {
+	if len(m) == 0 {
+		return ""
+	}
+	// Marshaling a map of strings cannot fail.
+	b, _ := yaml.Marshal(m)
+	return string(b)
+}
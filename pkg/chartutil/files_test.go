@@ -51,3 +51,75 @@ func TestNewFiles(t *testing.T) {
 		}
 	}
 }
+
+func getTestFiles() Files {
+	return Files{
+		"ship/captain.txt":   []byte("The Captain"),
+		"ship/stowaway.txt":  []byte("Legatt"),
+		"story/name.txt":     []byte("The Secret Sharer"),
+		"story/author.txt":   []byte("Joseph Conrad"),
+		"multiline/test.txt": []byte("bar\nfoo\n"),
+	}
+}
+
+func TestFileGlob(t *testing.T) {
+	f := getTestFiles()
+
+	matched := f.Glob("story/*")
+	if len(matched) != 2 {
+		t.Errorf("Expected two files in glob story/*, got %d", len(matched))
+	}
+	if got := matched.Get("story/author.txt"); got != "Joseph Conrad" {
+		t.Errorf("Expected Joseph Conrad, got %q", got)
+	}
+	if matched := f.Glob("*.txt"); len(matched) != 0 {
+		t.Errorf("Expected * not to match across directories, got %v", matched)
+	}
+}
+
+func TestFileLines(t *testing.T) {
+	f := getTestFiles()
+
+	lines := f.Lines("multiline/test.txt")
+	if len(lines) != 2 || lines[0] != "bar" || lines[1] != "foo" {
+		t.Errorf("Expected lines [bar foo], got %q", lines)
+	}
+	if lines := f.Lines("missing.txt"); len(lines) != 0 {
+		t.Errorf("Expected no lines for a missing file, got %q", lines)
+	}
+}
+
+func TestToConfig(t *testing.T) {
+	f := getTestFiles()
+
+	expect := "captain.txt: The Captain\nstowaway.txt: Legatt\n"
+	if got, err := f.Glob("ship/*").AsConfig(); err != nil || got != expect {
+		t.Errorf("Expected %q, got %q (%v)", expect, got, err)
+	}
+	if got, err := f.Glob("none/*").AsConfig(); err != nil || got != "" {
+		t.Errorf("Expected no data, got %q (%v)", got, err)
+	}
+}
+
+func TestToSecret(t *testing.T) {
+	f := getTestFiles()
+
+	expect := "author.txt: Sm9zZXBoIENvbnJhZA==\nname.txt: VGhlIFNlY3JldCBTaGFyZXI=\n"
+	if got, err := f.Glob("story/*").AsSecrets(); err != nil || got != expect {
+		t.Errorf("Expected %q, got %q (%v)", expect, got, err)
+	}
+}
+
+func TestToDataDuplicateKeys(t *testing.T) {
+	f := Files{
+		"conf/a/app.ini": []byte("a"),
+		"conf/b/app.ini": []byte("b"),
+	}
+	expect := `files "conf/a/app.ini" and "conf/b/app.ini" would both be stored under the key "app.ini"`
+	if _, err := f.AsConfig(); err == nil || err.Error() != expect {
+		t.Errorf("Expected error %q, got %v", expect, err)
+	}
+	if _, err := f.AsSecrets(); err == nil || err.Error() != expect {
+		t.Errorf("Expected error %q, got %v", expect, err)
+	}
+}
@@ -117,6 +117,8 @@ func loadFiles(files []*afile) (*chart.Chart, error) {
 			return c, errors.New("values.toml is illegal as of 2.0.0-alpha.2")
 		} else if f.name == "values.yaml" {
 			c.Values = &chart.Config{Raw: string(f.data)}
+		} else if f.name == SchemafileName {
+			c.Schema = f.data
 		} else if strings.HasPrefix(f.name, "templates/") {
 			c.Templates = append(c.Templates, &chart.Template{Name: f.name, Data: f.data})
 		} else if strings.HasPrefix(f.name, "charts/") {
@@ -107,6 +107,13 @@ func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
 		}
 	}
 
+	// Save values.schema.json
+	if len(c.Schema) > 0 {
+		if err := writeToTar(out, base+"/"+SchemafileName, c.Schema); err != nil {
+			return err
+		}
+	}
+
 	// Save templates
 	for _, f := range c.Templates {
 		n := filepath.Join(base, f.Name)
@@ -40,6 +40,7 @@ func TestSave(t *testing.T) {
 		Values: &chart.Config{
 			Raw: "ship: Pequod",
 		},
+		Schema: []byte(`{"properties": {"ship": {"type": "string"}}}`),
 	}
 
 	where, err := Save(c, tmp)
@@ -64,4 +65,7 @@ func TestSave(t *testing.T) {
 	if c2.Values.Raw != c.Values.Raw {
 		t.Fatal("Values data did not match")
 	}
+	if string(c2.Schema) != string(c.Schema) {
+		t.Fatal("Schema data did not match")
+	}
 }
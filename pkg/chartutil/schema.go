@@ -0,0 +1,464 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// ValidateAgainstSchema checks values against the values.schema.json of chrt,
+// and the table of values for each dependency against the schema of that
+// dependency.
+//
+// Every violation is reported along with the JSON path of the failing value,
+// as in "$.image.tag". Schemas may use the type, enum, properties, required,
+// additionalProperties, items, minimum, maximum, minLength, maxLength,
+// pattern, minItems and maxItems keywords of JSON Schema, along with
+// annotations such as title and description. A schema using any other
+// keyword, such as $ref or oneOf, is rejected rather than partly enforced.
+// The global values are only validated by schemas that declare them.
+func ValidateAgainstSchema(chrt *chart.Chart, values Values) error {
+	var errs []string
+	if err := validateChartValues(chrt, values, "$", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("values do not match %s:\n\t%s", SchemafileName, strings.Join(errs, "\n\t"))
+	}
+	return nil
+}
+
+// ValidateSchema checks that data is a JSON Schema that ValidateAgainstSchema
+// supports.
+func ValidateSchema(data []byte) error {
+	_, err := parseSchema(data)
+	return err
+}
+
+// schemaKeywords are the keywords a schema may use. Annotations that do not
+// affect validation are accepted as well.
+var schemaKeywords = map[string]bool{
+	"type":                 true,
+	"enum":                 true,
+	"properties":           true,
+	"required":             true,
+	"additionalProperties": true,
+	"items":                true,
+	"minimum":              true,
+	"maximum":              true,
+	"minLength":            true,
+	"maxLength":            true,
+	"pattern":              true,
+	"minItems":             true,
+	"maxItems":             true,
+
+	"$schema":     true,
+	"$comment":    true,
+	"title":       true,
+	"description": true,
+	"default":     true,
+	"examples":    true,
+}
+
+// schemaTypes are the names the type keyword may use.
+var schemaTypes = map[string]bool{
+	"null":    true,
+	"boolean": true,
+	"object":  true,
+	"array":   true,
+	"number":  true,
+	"integer": true,
+	"string":  true,
+}
+
+// schema is a parsed values.schema.json, or one of its subschemas.
+type schema struct {
+	// keywords holds the keywords of the schema, each checked to have a
+	// value of the right type.
+	keywords map[string]interface{}
+
+	pattern    *regexp.Regexp
+	properties map[string]*schema
+	// additional is set if additionalProperties is a schema.
+	additional *schema
+	items      *schema
+}
+
+// parseSchema parses and checks a values.schema.json.
+func parseSchema(data []byte) (*schema, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return compileSchema(m, "#")
+}
+
+// compileSchema checks the schema m, which is at the JSON pointer ptr, and
+// compiles its patterns.
+func compileSchema(m map[string]interface{}, ptr string) (*schema, error) {
+	s := &schema{keywords: m}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !schemaKeywords[k] {
+			return nil, fmt.Errorf("%s: unsupported keyword %q", ptr, k)
+		}
+		v := m[k]
+		invalid := func(expect string) error {
+			return fmt.Errorf("%s: %s must be %s", ptr, k, expect)
+		}
+		switch k {
+		case "type":
+			names, ok := v.([]interface{})
+			if !ok {
+				names = []interface{}{v}
+			}
+			for _, n := range names {
+				if name, ok := n.(string); !ok || !schemaTypes[name] {
+					return nil, invalid("a type name or a list of them")
+				}
+			}
+		case "enum":
+			if _, ok := v.([]interface{}); !ok {
+				return nil, invalid("a list")
+			}
+		case "required":
+			names, ok := v.([]interface{})
+			if !ok {
+				return nil, invalid("a list of property names")
+			}
+			for _, n := range names {
+				if _, ok := n.(string); !ok {
+					return nil, invalid("a list of property names")
+				}
+			}
+		case "minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems":
+			if _, ok := v.(float64); !ok {
+				return nil, invalid("a number")
+			}
+		case "pattern":
+			p, ok := v.(string)
+			if !ok {
+				return nil, invalid("a string")
+			}
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid pattern %q: %s", ptr, p, err)
+			}
+			s.pattern = re
+		case "properties":
+			props, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, invalid("an object")
+			}
+			s.properties = make(map[string]*schema, len(props))
+			for name, p := range props {
+				pm, ok := p.(map[string]interface{})
+				if !ok {
+					return nil, fmt.Errorf("%s: must be a schema", childPointer(childPointer(ptr, k), name))
+				}
+				ps, err := compileSchema(pm, childPointer(childPointer(ptr, k), name))
+				if err != nil {
+					return nil, err
+				}
+				s.properties[name] = ps
+			}
+		case "additionalProperties":
+			switch ap := v.(type) {
+			case bool:
+			case map[string]interface{}:
+				as, err := compileSchema(ap, childPointer(ptr, k))
+				if err != nil {
+					return nil, err
+				}
+				s.additional = as
+			default:
+				return nil, invalid("a boolean or a schema")
+			}
+		case "items":
+			im, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, invalid("a schema")
+			}
+			is, err := compileSchema(im, childPointer(ptr, k))
+			if err != nil {
+				return nil, err
+			}
+			s.items = is
+		}
+	}
+	return s, nil
+}
+
+// childPointer returns the JSON pointer of key in the object at ptr.
+func childPointer(ptr, key string) string {
+	key = strings.Replace(key, "~", "~0", -1)
+	return ptr + "/" + strings.Replace(key, "/", "~1", -1)
+}
+
+// validateChartValues validates the values of c and its dependencies,
+// appending violations to errs.
+func validateChartValues(c *chart.Chart, vals map[string]interface{}, path string, errs *[]string) error {
+	if len(c.Schema) > 0 {
+		s, err := parseSchema(c.Schema)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s of chart %s: %s", SchemafileName, c.Metadata.Name, err)
+		}
+		*errs = append(*errs, validateValue(s, withoutGlobals(s, vals), path)...)
+	}
+	for _, dep := range c.Dependencies {
+		name := dep.Metadata.Name
+		sub, _ := asTable(vals[name])
+		if sub == nil {
+			sub = map[string]interface{}{}
+		}
+		if err := validateChartValues(dep, sub, childPath(path, name), errs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withoutGlobals returns vals without the global values, unless schema
+// declares them. The globals of a parent chart are copied into the values of
+// every dependency, so a schema that does not allow additional properties
+// would otherwise reject them.
+func withoutGlobals(s *schema, vals map[string]interface{}) map[string]interface{} {
+	if _, ok := vals[GlobalKey]; !ok {
+		return vals
+	}
+	if _, ok := s.properties[GlobalKey]; ok {
+		return vals
+	}
+	v := make(map[string]interface{}, len(vals))
+	for k, val := range vals {
+		if k != GlobalKey {
+			v[k] = val
+		}
+	}
+	return v
+}
+
+// validateValue returns the violations of s by v, which is at path.
+func validateValue(s *schema, v interface{}, path string) []string {
+	schema := s.keywords
+	var errs []string
+	fail := func(format string, a ...interface{}) {
+		errs = append(errs, path+": "+fmt.Sprintf(format, a...))
+	}
+
+	if t, ok := schema["type"]; ok && !hasType(t, v) {
+		fail("expected %s, got %s", typeNames(t), typeOf(v))
+		return errs
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range enum {
+			if equal(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			b, _ := json.Marshal(enum)
+			fail("must be one of %s", b)
+		}
+	}
+
+	if f, ok := number(v); ok {
+		if min, ok := schema["minimum"].(float64); ok && f < min {
+			fail("must be at least %v", min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && f > max {
+			fail("must be at most %v", max)
+		}
+	}
+
+	if str, ok := v.(string); ok {
+		n := float64(utf8.RuneCountInString(str))
+		if min, ok := schema["minLength"].(float64); ok && n < min {
+			fail("must be at least %v characters long", min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && n > max {
+			fail("must be at most %v characters long", max)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(str) {
+			fail("must match pattern %q", s.pattern)
+		}
+	}
+
+	if a, ok := v.([]interface{}); ok {
+		n := float64(len(a))
+		if min, ok := schema["minItems"].(float64); ok && n < min {
+			fail("must have at least %v items", min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && n > max {
+			fail("must have at most %v items", max)
+		}
+		if s.items != nil {
+			for i, item := range a {
+				errs = append(errs, validateValue(s.items, item, fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	if t, ok := asTable(v); ok {
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, r := range required {
+				name := r.(string)
+				if _, ok := t[name]; !ok {
+					errs = append(errs, childPath(path, name)+": required property is missing")
+				}
+			}
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if ps, ok := s.properties[k]; ok {
+				errs = append(errs, validateValue(ps, t[k], childPath(path, k))...)
+				continue
+			}
+			if s.additional != nil {
+				errs = append(errs, validateValue(s.additional, t[k], childPath(path, k))...)
+			} else if ap, ok := schema["additionalProperties"].(bool); ok && !ap {
+				errs = append(errs, childPath(path, k)+": property is not allowed")
+			}
+		}
+	}
+
+	return errs
+}
+
+// identRe matches the keys that can be written as .key in a JSON path.
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// childPath returns the JSON path of key in the object at path.
+func childPath(path, key string) string {
+	if identRe.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// asTable returns v as a table, if it is one.
+func asTable(v interface{}) (map[string]interface{}, bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return t, true
+	case Values:
+		return t, true
+	}
+	return nil, false
+}
+
+// number returns v as a float64, if it is a number.
+func number(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// typeOf returns the JSON Schema type of v.
+func typeOf(v interface{}) string {
+	if f, ok := number(v); ok {
+		if f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+	if _, ok := asTable(v); ok {
+		return "object"
+	}
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// hasType reports whether v has the type, or one of the types, in t.
+func hasType(t interface{}, v interface{}) bool {
+	actual := typeOf(v)
+	for _, name := range typeList(t) {
+		if name == actual || name == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// typeList returns the type names of the type keyword t.
+func typeList(t interface{}) []string {
+	switch t := t.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		names := []string{}
+		for _, n := range t {
+			if s, ok := n.(string); ok {
+				names = append(names, s)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// typeNames describes the type keyword t.
+func typeNames(t interface{}) string {
+	return strings.Join(typeList(t), " or ")
+}
+
+// equal reports whether two values are equal, comparing numbers by value.
+func equal(a, b interface{}) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestLoadSchema(t *testing.T) {
+	c, err := Load("testdata/albatross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Schema) == 0 {
+		t.Fatal("Expected the schema to be loaded")
+	}
+	for _, f := range c.Files {
+		if f.TypeUrl == SchemafileName {
+			t.Errorf("Expected the schema not to be loaded as a file")
+		}
+	}
+
+	vals, err := CoalesceValues(c, c.Values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAgainstSchema(c, vals); err != nil {
+		t.Errorf("Expected the default values to be valid, got %s", err)
+	}
+}
+
+func TestValidateValue(t *testing.T) {
+	schema := `{
+		"type": "object",
+		"required": ["replicaCount"],
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 1, "maximum": 10},
+			"image": {
+				"type": "object",
+				"properties": {
+					"tag": {"type": "string", "pattern": "^v"},
+					"pullPolicy": {"enum": ["Always", "IfNotPresent"]}
+				}
+			},
+			"ports": {"type": "array", "maxItems": 2, "items": {"type": "integer"}},
+			"ratio": {"type": ["number", "null"]},
+			"name": {"type": "string", "maxLength": 3}
+		},
+		"additionalProperties": false
+	}`
+
+	tests := []struct {
+		values string
+		expect []string
+	}{
+		{`{"replicaCount": 3, "image": {"tag": "v1", "pullPolicy": "Always"}, "ports": [80, 443], "ratio": null}`, nil},
+		{`{"replicaCount": 3, "ratio": 0.5, "name": "bob"}`, nil},
+		{`{"replicaCont": 3}`, []string{
+			"$.replicaCount: required property is missing",
+			"$.replicaCont: property is not allowed",
+		}},
+		{`{"replicaCount": "3"}`, []string{"$.replicaCount: expected integer, got string"}},
+		{`{"replicaCount": 1.5}`, []string{"$.replicaCount: expected integer, got number"}},
+		{`{"replicaCount": 11}`, []string{"$.replicaCount: must be at most 10"}},
+		{`{"replicaCount": 1, "image": {"tag": "1.0", "pullPolicy": "Never"}}`, []string{
+			`$.image.pullPolicy: must be one of ["Always","IfNotPresent"]`,
+			`$.image.tag: must match pattern "^v"`,
+		}},
+		{`{"replicaCount": 1, "ports": [80, "http", 443]}`, []string{
+			"$.ports: must have at most 2 items",
+			"$.ports[1]: expected integer, got string",
+		}},
+		{`{"replicaCount": 1, "ratio": true, "name": "alice"}`, []string{
+			"$.name: must be at most 3 characters long",
+			"$.ratio: expected number or null, got boolean",
+		}},
+	}
+
+	s, err := parseSchema([]byte(schema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		var v map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.values), &v); err != nil {
+			t.Fatal(err)
+		}
+		errs := validateValue(s, v, "$")
+		if strings.Join(errs, "\n") != strings.Join(tt.expect, "\n") {
+			t.Errorf("For %s expected %q, got %q", tt.values, tt.expect, errs)
+		}
+	}
+}
+
+func TestParseSchema(t *testing.T) {
+	tests := []struct {
+		schema string
+		expect string
+	}{
+		{`{"title": "values", "description": "d", "default": {}, "properties": {"a": {"type": ["string", "null"]}}}`, ""},
+		{`{"definitions": {"port": {"type": "integer"}}}`, `#: unsupported keyword "definitions"`},
+		{`{"properties": {"port": {"$ref": "#/definitions/port"}}}`, `#/properties/port: unsupported keyword "$ref"`},
+		{`{"items": {"oneOf": [{"type": "string"}]}}`, `#/items: unsupported keyword "oneOf"`},
+		{`{"additionalProperties": {"not": {"type": "string"}}}`, `#/additionalProperties: unsupported keyword "not"`},
+		{`{"properties": {"a/b": {"const": 1}}}`, `#/properties/a~1b: unsupported keyword "const"`},
+		{`{"patternProperties": {}}`, `#: unsupported keyword "patternProperties"`},
+		{`{"exclusiveMinimum": 0}`, `#: unsupported keyword "exclusiveMinimum"`},
+		{`{"format": "uri"}`, `#: unsupported keyword "format"`},
+		{`{"type": "int"}`, `#: type must be a type name or a list of them`},
+		{`{"minimum": "1"}`, `#: minimum must be a number`},
+		{`{"items": [{"type": "string"}]}`, `#: items must be a schema`},
+		{`{"properties": {"tag": {"pattern": "("}}}`, `#/properties/tag: invalid pattern "("`},
+	}
+	for _, tt := range tests {
+		err := ValidateSchema([]byte(tt.schema))
+		if tt.expect == "" {
+			if err != nil {
+				t.Errorf("For %s expected no error, got %s", tt.schema, err)
+			}
+			continue
+		}
+		if err == nil || !strings.HasPrefix(err.Error(), tt.expect) {
+			t.Errorf("For %s expected %q, got %v", tt.schema, tt.expect, err)
+		}
+	}
+}
+
+func TestValidateAgainstSchemaDependencies(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "crew"},
+				Schema:   []byte(`{"properties": {"size": {"type": "integer"}}}`),
+			},
+		},
+	}
+
+	if err := ValidateAgainstSchema(c, Values{"crew": map[string]interface{}{"size": int64(3)}}); err != nil {
+		t.Errorf("Expected the values to be valid, got %s", err)
+	}
+
+	err := ValidateAgainstSchema(c, Values{"crew": map[string]interface{}{"size": "three"}})
+	expect := "values do not match values.schema.json:\n\t$.crew.size: expected integer, got string"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+
+	c.Dependencies[0].Schema = []byte(`{`)
+	if err := ValidateAgainstSchema(c, Values{}); err == nil || !strings.Contains(err.Error(), "cannot parse values.schema.json of chart crew") {
+		t.Errorf("Expected a schema parse error, got %v", err)
+	}
+
+	// A keyword that is not supported must not be silently ignored.
+	c.Dependencies[0].Schema = []byte(`{"properties": {"size": {"anyOf": [{"type": "integer"}]}}}`)
+	err = ValidateAgainstSchema(c, Values{"crew": map[string]interface{}{"size": "three"}})
+	expect = `cannot parse values.schema.json of chart crew: #/properties/size: unsupported keyword "anyOf"`
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
+
+func TestValidateAgainstSchemaGlobals(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Values:   &chart.Config{Raw: "global:\n  port: 80\ncrew:\n  size: 3\n"},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "crew"},
+				Schema:   []byte(`{"properties": {"size": {"type": "integer"}}, "required": ["size"], "additionalProperties": false}`),
+			},
+		},
+	}
+
+	vals, err := CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateAgainstSchema(c, vals); err != nil {
+		t.Errorf("Expected the parent's globals to be allowed, got %s", err)
+	}
+
+	// Globals are validated if the schema declares them.
+	c.Dependencies[0].Schema = []byte(`{"properties": {"global": {"properties": {"port": {"type": "string"}}}}, "additionalProperties": false}`)
+	err = ValidateAgainstSchema(c, vals)
+	expect := "values do not match values.schema.json:\n\t$.crew.global.port: expected string, got integer\n\t$.crew.size: property is not allowed"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
@@ -137,6 +137,10 @@ func ReadValuesFile(filename string) (Values, error) {
 //	- Scalar values and arrays are replaced, maps are merged
 //	- A chart has access to all of the variables for it, as well as all of
 //		the values destined for its dependencies.
+//	- An explicit null removes the key it is set on, so that a default such as
+//		a nodeSelector or an annotation can be deleted. This holds at any depth,
+//		and a null set on a subchart's value in the parent chart's values
+//		removes the subchart's default.
 func CoalesceValues(chrt *chart.Chart, vals *chart.Config) (Values, error) {
 	cvals := Values{}
 	// Parse values if not nil. We merge these at the top level because
@@ -151,7 +155,9 @@ func CoalesceValues(chrt *chart.Chart, vals *chart.Config) (Values, error) {
 
 	cvals = coalesceDeps(chrt, cvals)
 
-	return cvals, nil
+	// Nulls have overridden the defaults of the chart and its dependencies, so
+	// they can be removed now.
+	return pruneNulls(cvals), nil
 }
 
 // coalesce coalesces the dest values and the chart values, giving priority to the dest values.
@@ -166,12 +172,14 @@ func coalesce(ch *chart.Chart, dest map[string]interface{}) map[string]interface
 // coalesceDeps coalesces the dependencies of the given chart.
 func coalesceDeps(chrt *chart.Chart, dest map[string]interface{}) map[string]interface{} {
 	for _, subchart := range chrt.Dependencies {
-		if c, ok := dest[subchart.Metadata.Name]; !ok {
-			// If dest doesn't already have the key, create it.
+		if c, ok := dest[subchart.Metadata.Name]; !ok || c == nil {
+			// If dest doesn't already have the key, create it. A null table
+			// of values is treated as an empty one, so the subchart keeps
+			// its defaults.
 			dest[subchart.Metadata.Name] = map[string]interface{}{}
 		} else if !istable(c) {
 			log.Printf("error: type mismatch on %s: %t", subchart.Metadata.Name, c)
-			return dest
+			continue
 		}
 		if dv, ok := dest[subchart.Metadata.Name]; ok {
 			dvmap := dv.(map[string]interface{})
@@ -206,18 +214,27 @@ func coalesceGlobals(dest, src map[string]interface{}) map[string]interface{} {
 		return dg
 	}
 
-	// We manually copy (instead of using coalesceTables) because (a) we need
-	// to prevent loops, and (b) we disallow nesting tables under globals.
-	// Globals should _just_ be k/v pairs.
+	// We manually copy (instead of using CoalesceTables) because the parent's
+	// globals take precedence over the subchart's, and because the subchart
+	// must get its own copy of each table. Sharing the parent's tables would
+	// let the subchart's values be merged into them, and could make a table
+	// contain itself.
 	for key, val := range sg {
 		if istable(val) {
-			log.Printf("warning: nested values are illegal in globals (%s)", key)
+			vv := copyTable(val.(map[string]interface{}))
+			if dv, ok := dg[key]; ok && istable(dv) {
+				// The parent's values win, so the subchart's table is merged
+				// into the copy.
+				CoalesceTables(vv, dv.(map[string]interface{}))
+			} else if ok && dv != nil {
+				log.Printf("warning: replacing non-table global %s with a table", key)
+			}
+			dg[key] = vv
 			continue
-		} else if dv, ok := dg[key]; ok && istable(dv) {
-			log.Printf("warning: nested values are illegal in globals (%s)", key)
+		} else if dv, ok := dg[key]; ok && istable(dv) && val != nil {
+			log.Printf("warning: cannot overwrite table with non table for global %s (%v)", key, val)
 			continue
 		}
-		// TODO: Do we need to do any additional checking on the value?
 		dg[key] = val
 	}
 	dest[GlobalKey] = dg
@@ -225,6 +242,18 @@ func coalesceGlobals(dest, src map[string]interface{}) map[string]interface{} {
 
 }
 
+// copyTable returns a deep copy of the tables in t.
+func copyTable(t map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(t))
+	for k, v := range t {
+		if vt, ok := v.(map[string]interface{}); ok {
+			v = copyTable(vt)
+		}
+		c[k] = v
+	}
+	return c
+}
+
 // coalesceValues builds up a values map for a particular chart.
 //
 // Values in v will override the values in the chart.
@@ -256,32 +285,40 @@ func coalesceValues(c *chart.Chart, v map[string]interface{}) map[string]interfa
 			}
 			// Because v has higher precedence than nv, dest values override src
 			// values.
-			coalesceTables(dest, src)
+			CoalesceTables(dest, src)
 		}
 	}
 	return v
 }
 
-// coalesceTables merges a source map into a destination map.
+// CoalesceTables merges a source map into a destination map.
 //
-// dest is considered authoritative.
-func coalesceTables(dst, src map[string]interface{}) map[string]interface{} {
+// dst is considered authoritative: its values are kept, tables in src are
+// merged into the tables of dst, and keys missing from dst are copied from src.
+// A null in dst is kept, so that it still removes a chart's default when the
+// result is coalesced with the chart's values.
+func CoalesceTables(dst, src map[string]interface{}) map[string]interface{} {
 	// Because dest has higher precedence than src, dest values override src
 	// values.
 	for key, val := range src {
+		dv, ok := dst[key]
+		if ok && dv == nil {
+			// An explicit null overrides any value.
+			continue
+		}
 		if istable(val) {
-			if innerdst, ok := dst[key]; !ok {
+			if !ok {
 				dst[key] = val
-			} else if istable(innerdst) {
-				coalesceTables(innerdst.(map[string]interface{}), val.(map[string]interface{}))
+			} else if istable(dv) {
+				CoalesceTables(dv.(map[string]interface{}), val.(map[string]interface{}))
 			} else {
 				log.Printf("warning: cannot overwrite table with non table for %s (%v)", key, val)
 			}
 			continue
-		} else if dv, ok := dst[key]; ok && istable(dv) {
+		} else if ok && istable(dv) {
 			log.Printf("warning: destination for %s is a table. Ignoring non-table value %v", key, val)
 			continue
-		} else if !ok { // <- ok is still in scope from preceding conditional.
+		} else if !ok {
 			dst[key] = val
 			continue
 		}
@@ -289,6 +326,18 @@ func coalesceTables(dst, src map[string]interface{}) map[string]interface{} {
 	return dst
 }
 
+// pruneNulls removes the keys set to null from v and the tables in it.
+func pruneNulls(v map[string]interface{}) map[string]interface{} {
+	for key, val := range v {
+		if val == nil {
+			delete(v, key)
+		} else if t, ok := val.(map[string]interface{}); ok {
+			pruneNulls(t)
+		}
+	}
+	return v
+}
+
 // ReleaseOptions represents the additional release options needed
 // for the composition of the final values struct
 type ReleaseOptions struct {
@@ -298,7 +347,11 @@ type ReleaseOptions struct {
 }
 
 // ToRenderValues composes the struct from the data coming from the Releases, Charts and Values files
-func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOptions) (Values, error) {
+//
+// caps are made available to the templates as .Capabilities. The values are
+// validated against the values.schema.json files of the chart and its
+// dependencies.
+func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOptions, caps *Capabilities) (Values, error) {
 
 	top := map[string]interface{}{
 		"Release": map[string]interface{}{
@@ -307,8 +360,9 @@ func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOp
 			"Namespace": options.Namespace,
 			"Service":   "Tiller",
 		},
-		"Chart": chrt.Metadata,
-		"Files": NewFiles(chrt.Files),
+		"Chart":        chrt.Metadata,
+		"Files":        NewFiles(chrt.Files),
+		"Capabilities": caps,
 	}
 
 	vals, err := CoalesceValues(chrt, chrtVals)
@@ -316,6 +370,10 @@ func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOp
 		return top, err
 	}
 
+	if err := ValidateAgainstSchema(chrt, vals); err != nil {
+		return top, err
+	}
+
 	top["Values"] = vals
 	return top, nil
 }
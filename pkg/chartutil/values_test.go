@@ -106,7 +106,9 @@ where:
 		Namespace: "al Basrah",
 	}
 
-	res, err := ToRenderValues(c, v, o)
+	caps := &Capabilities{APIVersions: NewVersionSet("v1", "batch/v2alpha1")}
+
+	res, err := ToRenderValues(c, v, o, caps)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -121,6 +123,9 @@ where:
 	if data := res["Files"].(Files)["scheherazade/shahryar.txt"]; string(data) != "1,001 Nights" {
 		t.Errorf("Expected file '1,001 Nights', got %q", string(data))
 	}
+	if !res["Capabilities"].(*Capabilities).APIVersions.Has("batch/v2alpha1") {
+		t.Error("Expected Capabilities to have batch/v2alpha1")
+	}
 
 	var vals Values
 	vals = res["Values"].(Values)
@@ -301,6 +306,249 @@ func TestCoalesceValues(t *testing.T) {
 	}
 }
 
+func TestCoalesceValuesNulls(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults string
+		subchart string
+		sibling  string
+		vals     string
+		expect   string
+	}{
+		{
+			name:     "no nulls",
+			defaults: "replicas: 1\nnodeSelector:\n  disk: ssd\n",
+			vals:     "replicas: 2\n",
+			expect:   "nodeSelector:\n  disk: ssd\nreplicas: 2\nsub:\n  global: {}\n",
+		},
+		{
+			name:     "top-level table",
+			defaults: "replicas: 1\nnodeSelector:\n  disk: ssd\n",
+			vals:     "nodeSelector: null\n",
+			expect:   "replicas: 1\nsub:\n  global: {}\n",
+		},
+		{
+			name:     "nested key",
+			defaults: "annotations:\n  a: one\n  b: two\n",
+			vals:     "annotations:\n  a: null\n",
+			expect:   "annotations:\n  b: two\nsub:\n  global: {}\n",
+		},
+		{
+			name:     "deeply nested key",
+			defaults: "pod:\n  spec:\n    tolerations: []\n    nodeSelector:\n      disk: ssd\n",
+			vals:     "pod:\n  spec:\n    nodeSelector:\n      disk: null\n",
+			expect:   "pod:\n  spec:\n    nodeSelector: {}\n    tolerations: []\nsub:\n  global: {}\n",
+		},
+		{
+			name:   "key without a default",
+			vals:   "extra: null\n",
+			expect: "sub:\n  global: {}\n",
+		},
+		{
+			name:     "subchart default",
+			subchart: "port: 80\nannotations:\n  a: one\n",
+			vals:     "sub:\n  annotations: null\n",
+			expect:   "sub:\n  global: {}\n  port: 80\n",
+		},
+		{
+			name:     "subchart default overridden by the parent",
+			defaults: "sub:\n  annotations:\n    a: two\n",
+			subchart: "port: 80\nannotations:\n  a: one\n",
+			vals:     "sub:\n  annotations:\n    a: null\n",
+			expect:   "sub:\n  annotations: {}\n  global: {}\n  port: 80\n",
+		},
+		{
+			name:     "subchart default removed by the parent",
+			defaults: "sub:\n  port: null\n",
+			subchart: "port: 80\nhost: example.com\n",
+			expect:   "sub:\n  global: {}\n  host: example.com\n",
+		},
+		{
+			name:     "list elements are kept",
+			defaults: "args: [a]\n",
+			vals:     "args: [null, b]\n",
+			expect:   "args:\n- null\n- b\nsub:\n  global: {}\n",
+		},
+		{
+			name:     "null subchart",
+			subchart: "port: 80\n",
+			sibling:  "port: 90\n",
+			vals:     "sub: null\n",
+			expect:   "sub:\n  global: {}\n  port: 80\nzzz:\n  global: {}\n  port: 90\n",
+		},
+		{
+			name:     "subchart that is not a table",
+			subchart: "port: 80\n",
+			sibling:  "port: 90\n",
+			vals:     "sub: 3\n",
+			expect:   "sub: 3\nzzz:\n  global: {}\n  port: 90\n",
+		},
+	}
+
+	for _, tt := range tests {
+		c := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "parent"},
+			Values:   &chart.Config{Raw: tt.defaults},
+			Dependencies: []*chart.Chart{
+				{
+					Metadata: &chart.Metadata{Name: "sub"},
+					Values:   &chart.Config{Raw: tt.subchart},
+				},
+			},
+		}
+		if tt.sibling != "" {
+			// A second subchart, whose defaults must not be lost.
+			c.Dependencies = append(c.Dependencies, &chart.Chart{
+				Metadata: &chart.Metadata{Name: "zzz"},
+				Values:   &chart.Config{Raw: tt.sibling},
+			})
+		}
+
+		v, err := CoalesceValues(c, &chart.Config{Raw: tt.vals})
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		out, err := v.YAML()
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if out != tt.expect {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tt.name, tt.expect, out)
+		}
+	}
+}
+
+func TestCoalesceTablesKeepsNulls(t *testing.T) {
+	dst := map[string]interface{}{
+		"name": nil,
+		"boat": map[string]interface{}{"mast": nil},
+	}
+	src := map[string]interface{}{
+		"name": "Ishmael",
+		"boat": map[string]interface{}{"mast": true, "sails": 3},
+	}
+	CoalesceTables(dst, src)
+
+	if v, ok := dst["name"]; !ok || v != nil {
+		t.Errorf("Expected name to stay null, got %v", v)
+	}
+	boat := dst["boat"].(map[string]interface{})
+	if v, ok := boat["mast"]; !ok || v != nil {
+		t.Errorf("Expected boat.mast to stay null, got %v", v)
+	}
+	if boat["sails"] != 3 {
+		t.Errorf("Expected boat.sails to be merged, got %v", boat["sails"])
+	}
+}
+
+func TestCoalesceNestedGlobals(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby"},
+		Values: &chart.Config{Raw: `
+global:
+  image:
+    registry: quay.io
+    pullPolicy: Always
+  ingress:
+    annotations:
+      class: nginx
+`},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "pequod"},
+				Values: &chart.Config{Raw: `
+global:
+  image:
+    registry: docker.io
+    tag: latest
+  ingress:
+    annotations:
+      tls: "true"
+`},
+				Dependencies: []*chart.Chart{
+					{
+						Metadata: &chart.Metadata{Name: "ahab"},
+						Values: &chart.Config{Raw: `
+global:
+  image:
+    tag: stable
+    digest: none
+`},
+					},
+				},
+			},
+			{
+				Metadata: &chart.Metadata{Name: "spouter"},
+			},
+		},
+	}
+
+	vals := &chart.Config{Raw: `
+global:
+  image:
+    pullPolicy: IfNotPresent
+pequod:
+  global:
+    ingress:
+      annotations:
+        class: traefik
+        path: /whale
+`}
+
+	v, err := CoalesceValues(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		tpl    string
+		expect string
+	}{
+		{"{{.global.image.registry}}", "quay.io"},
+		{"{{.global.image.pullPolicy}}", "IfNotPresent"},
+		{"{{.global.image.tag}}", "<no value>"},
+		{"{{.global.ingress.annotations.class}}", "nginx"},
+		{"{{.global.ingress.annotations.tls}}", "<no value>"},
+
+		{"{{.pequod.global.image.registry}}", "quay.io"},
+		{"{{.pequod.global.image.pullPolicy}}", "IfNotPresent"},
+		{"{{.pequod.global.image.tag}}", "latest"},
+		{"{{.pequod.global.ingress.annotations.class}}", "nginx"},
+		{"{{.pequod.global.ingress.annotations.path}}", "/whale"},
+		{"{{.pequod.global.ingress.annotations.tls}}", "true"},
+
+		{"{{.pequod.ahab.global.image.registry}}", "quay.io"},
+		{"{{.pequod.ahab.global.image.pullPolicy}}", "IfNotPresent"},
+		{"{{.pequod.ahab.global.image.tag}}", "latest"},
+		{"{{.pequod.ahab.global.image.digest}}", "none"},
+		{"{{.pequod.ahab.global.ingress.annotations.class}}", "nginx"},
+		{"{{.pequod.ahab.global.ingress.annotations.path}}", "/whale"},
+		{"{{.pequod.ahab.global.ingress.annotations.tls}}", "true"},
+
+		{"{{.spouter.global.image.registry}}", "quay.io"},
+		{"{{.spouter.global.image.tag}}", "<no value>"},
+		{"{{.spouter.global.ingress.annotations.class}}", "nginx"},
+		{"{{.spouter.global.ingress.annotations.tls}}", "<no value>"},
+		{"{{.spouter.global.ingress.annotations.path}}", "<no value>"},
+	}
+
+	for _, tt := range tests {
+		if o, err := ttpl(tt.tpl, v); err != nil || o != tt.expect {
+			t.Errorf("Expected %q to expand to %q, got %q", tt.tpl, tt.expect, o)
+		}
+	}
+
+	// Each chart must have its own copy of the global tables.
+	parent := v["global"].(map[string]interface{})["image"].(map[string]interface{})
+	pequod := v["pequod"].(map[string]interface{})["global"].(map[string]interface{})["image"].(map[string]interface{})
+	pequod["registry"] = "changed"
+	if parent["registry"] != "quay.io" {
+		t.Errorf("Expected the subchart's globals not to be shared with the parent")
+	}
+}
+
 func TestCoalesceTables(t *testing.T) {
 	dst := map[string]interface{}{
 		"name": "Ishmael",
@@ -327,7 +575,7 @@ func TestCoalesceTables(t *testing.T) {
 
 	// What we expect is that anything in dst overrides anything in src, but that
 	// otherwise the values are coalesced.
-	coalesceTables(dst, src)
+	CoalesceTables(dst, src)
 
 	if dst["name"] != "Ishmael" {
 		t.Errorf("Unexpected name: %s", dst["name"])
@@ -0,0 +1,100 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// Cache holds the parsed templates of recently rendered charts.
+//
+// Entries are keyed by a digest of a chart's template files, so a chart is
+// parsed again whenever its templates change. Only parse trees are cached;
+// values and template sets are never shared between renders. A Cache is safe
+// for concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+// cacheEntry is an element of Cache.lru.
+type cacheEntry struct {
+	key string
+	p   *parsed
+}
+
+// NewCache creates a Cache that holds the templates of up to size charts,
+// evicting the least recently used chart beyond that. Subcharts count as
+// charts of their own.
+func NewCache(size int) *Cache {
+	return &Cache{
+		size:    size,
+		lru:     list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Len returns the number of charts in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *Cache) get(key string) (*parsed, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return el.Value.(*cacheEntry).p, true
+}
+
+func (c *Cache) add(key string, p *parsed) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		// A concurrent render parsed the same templates.
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, p: p})
+	for c.lru.Len() > c.size {
+		el := c.lru.Back()
+		c.lru.Remove(el)
+		delete(c.entries, el.Value.(*cacheEntry).key)
+	}
+}
+
+// digest returns a key that identifies the names and contents of files.
+func digest(files []string, tpls map[string]renderable) string {
+	h := sha256.New()
+	for _, fname := range files {
+		// Length prefixes keep different names and contents from colliding.
+		fmt.Fprintf(h, "%d:%s%d:", len(fname), fname, len(tpls[fname].tpl))
+		h.Write([]byte(tpls[fname].tpl))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
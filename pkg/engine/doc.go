@@ -18,6 +18,7 @@ limitations under the License.
 
 Tiller provides a simple interface for taking a Chart and rendering its templates.
 The 'engine' package implements this interface using Go's built-in 'text/template'
-package.
+package. It also provides Raw, which renders templates unmodified, and Exec,
+which runs an external program to render a chart.
 */
 package engine // import "k8s.io/helm/pkg/engine"
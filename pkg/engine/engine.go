@@ -18,10 +18,15 @@ package engine
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"path"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
+	"text/template/parse"
+	"time"
 
 	"github.com/Masterminds/sprig"
 	"github.com/ghodss/yaml"
@@ -30,7 +35,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
-// Engine is an implementation of 'cmd/tiller/environment'.Engine that uses Go templates.
+// Engine is an implementation of 'pkg/tiller/environment'.Engine that uses Go templates.
 type Engine struct {
 	// FuncMap contains the template functions that will be passed to each
 	// render call. This may only be modified before the first call to Render.
@@ -38,6 +43,28 @@ type Engine struct {
 	// If strict is enabled, template rendering will fail if a template references
 	// a value that was not passed in.
 	Strict bool
+	// Cache, if not nil, keeps the parsed templates of each chart for reuse
+	// by later renders of the same templates. It must not be shared by
+	// engines with different FuncMaps.
+	Cache *Cache
+	// MaxRenderTime limits how long a call to Render may take. It is enforced
+	// before every action and at the start of every loop iteration. Zero
+	// means no limit.
+	MaxRenderTime time.Duration
+	// MaxIncludeDepth limits how deeply include and tpl calls may nest. Zero
+	// means no limit.
+	MaxIncludeDepth int
+	// MaxOutputSize limits, in bytes, the total output of a call to Render
+	// together with the output of the include and tpl calls in progress. It
+	// separately limits the memory that template functions may allocate in a
+	// call to Render, counting the strings, lists and maps they return, which
+	// also bounds the number of loop iterations. Zero means no limit.
+	MaxOutputSize int
+
+	// base is an empty template set with the FuncMap. Checking the functions
+	// is costly, so each chart's template set is cloned from it.
+	base     *template.Template
+	baseOnce sync.Once
 }
 
 // New creates a new Go template Engine instance.
@@ -46,7 +73,8 @@ type Engine struct {
 // first invocation of Render.
 //
 // The FuncMap sets all of the Sprig functions except for those that provide
-// access to the underlying OS (env, expandenv).
+// access to the underlying OS (env, expandenv), as well as toYaml and required.
+// The include and tpl functions are added when templates are rendered.
 func New() *Engine {
 	f := sprig.TxtFuncMap()
 	delete(f, "env")
@@ -54,6 +82,7 @@ func New() *Engine {
 
 	// Add a function to convert to YAML:
 	f["toYaml"] = toYaml
+	f["required"] = required
 	return &Engine{
 		FuncMap: f,
 	}
@@ -68,6 +97,18 @@ func toYaml(v interface{}) string {
 	return string(data)
 }
 
+// required returns val, or fails rendering with warn if val is missing or an
+// empty string.
+func required(warn string, val interface{}) (interface{}, error) {
+	if val == nil {
+		return nil, errors.New(warn)
+	}
+	if s, ok := val.(string); ok && s == "" {
+		return nil, errors.New(warn)
+	}
+	return val, nil
+}
+
 // Render takes a chart, optional values, and value overrides, and attempts to render the Go templates.
 //
 // Render can be called repeatedly on the same engine.
@@ -87,6 +128,8 @@ func toYaml(v interface{}) string {
 // that section of the values will be passed into the "foo" chart. And if that
 // section contains a value named "bar", that value will be passed on to the
 // bar chart during render time.
+//
+// Templates that fail to parse or execute are reported as a *RenderError.
 func (e *Engine) Render(chrt *chart.Chart, values chartutil.Values) (map[string]string, error) {
 	// Render the charts
 	tmap := allTemplates(chrt, values)
@@ -99,57 +142,189 @@ type renderable struct {
 	tpl string
 	// vals are the values to be supplied to the template.
 	vals chartutil.Values
+	// chart is the path of the chart the template belongs to, e.g.
+	// "parent/charts/child". It is the namespace of the named templates that
+	// the template defines.
+	chart string
+}
+
+// namespace holds the templates of one chart.
+type namespace struct {
+	// id is the path of the chart, as in renderable.chart.
+	id string
+	// files maps each template file of the chart to its parse tree.
+	files map[string]*parse.Tree
+	// defines maps each named template of the chart to its parse tree.
+	defines map[string]*parse.Tree
+	// ambiguous maps names the chart does not define, but several other
+	// charts do, to the charts that define them.
+	ambiguous map[string][]string
+	// budget tracks the resources used by the render.
+	budget *budget
+	// calls records the errors of the include and tpl calls of the render.
+	calls *callErrors
+	// t is the template set the chart's templates are executed in.
+	t *template.Template
+}
+
+// name returns the chart name used to qualify the chart's named templates.
+func (n *namespace) name() string {
+	return path.Base(n.id)
+}
+
+// depth returns how deeply the chart is nested in the chart being rendered.
+func (n *namespace) depth() int {
+	return strings.Count(n.id, "/charts/")
 }
 
 // alterFuncMap takes the Engine's FuncMap and adds context-specific functions.
 //
-// The resulting FuncMap is only valid for the passed-in template.
-func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
+// The resulting FuncMap is only valid for the passed-in namespace.
+func (e *Engine) alterFuncMap(ns *namespace) template.FuncMap {
 	// Clone the func map because we are adding context-specific functions.
 	var funcMap template.FuncMap = map[string]interface{}{}
 	for k, v := range e.FuncMap {
 		funcMap[k] = v
 	}
+	for k, v := range e.namespaceFuncs(ns) {
+		funcMap[k] = v
+	}
+	return funcMap
+}
+
+// namespaceFuncs returns the functions that depend on the namespace they are
+// called from, or on the budget of the render.
+func (e *Engine) namespaceFuncs(ns *namespace) template.FuncMap {
+	funcMap := budgetFuncs(ns.budget, e.FuncMap)
 
-	// Add the 'include' function here so we can close over t.
-	funcMap["include"] = func(name string, data interface{}) string {
+	// Add the 'include' function here so we can close over the namespace.
+	// Errors abort the render rather than being written into the output.
+	funcMap["include"] = ns.calls.record(func(name string, data interface{}) (string, error) {
+		if charts, ok := ns.ambiguous[name]; ok {
+			return "", fmt.Errorf("template %q is defined in charts %s; qualify it as \"<chart>:%s\"", name, strings.Join(charts, ", "), name)
+		}
+		if err := ns.budget.enter(); err != nil {
+			return "", err
+		}
+		defer ns.budget.leave()
 		buf := bytes.NewBuffer(nil)
-		if err := t.ExecuteTemplate(buf, name, data); err != nil {
-			buf.WriteString(err.Error())
+		w := ns.budget.writer(buf)
+		defer w.release()
+		if err := ns.t.ExecuteTemplate(w, name, data); err != nil {
+			return "", err
 		}
-		return buf.String()
-	}
+		return buf.String(), nil
+	})
+
+	// Add the 'tpl' function, which renders a string as a template with the
+	// given values. The string may use the templates defined in the chart.
+	funcMap["tpl"] = ns.calls.record(func(tpl string, data interface{}) (string, error) {
+		if err := ns.budget.enter(); err != nil {
+			return "", err
+		}
+		defer ns.budget.leave()
+		// Clone so that parsing the string does not change the templates that
+		// are being rendered.
+		nt, err := ns.t.Clone()
+		if err != nil {
+			return "", err
+		}
+		known := map[*parse.Tree]bool{}
+		for _, t := range nt.Templates() {
+			known[t.Tree] = true
+		}
+		nt, err = nt.New("tpl").Parse(tpl)
+		if err != nil {
+			return "", fmt.Errorf("parse error in tpl: %s", err)
+		}
+		for _, t := range nt.Templates() {
+			if t.Tree != nil && !known[t.Tree] {
+				instrument(t.Tree.Root, false)
+			}
+		}
+		buf := bytes.NewBuffer(nil)
+		w := ns.budget.writer(buf)
+		defer w.release()
+		if err := nt.Execute(w, data); err != nil {
+			return "", err
+		}
+		return strings.Replace(buf.String(), "<no value>", "", -1), nil
+	})
 
 	return funcMap
 }
 
 // render takes a map of templates/values and renders them.
+//
+// Every template file is parsed first, and then every template is executed.
+// This makes it possible for templates to share named templates ("define"
+// blocks) regardless of the order of the files.
+//
+// Named templates are scoped to the chart that defines them, so that charts
+// which define the same name do not override each other. A template resolves
+// a name from its own chart first, and otherwise from the one other chart
+// that defines it. The name is ambiguous if several other charts define it,
+// and can then be qualified with the chart name, as in "mysql:fullname".
+// Defining a name twice in the same chart is an error.
+//
+// Templates that fail to parse or execute are reported as a *RenderError.
 func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
-	// Basically, what we do here is start with an empty parent template and then
-	// build up a list of templates -- one for each file. Once all of the templates
-	// have been parsed, we loop through again and execute every template.
-	//
-	// The idea with this process is to make it possible for more complex templates
-	// to share common blocks, but to make the entire thing feel like a file-based
-	// template engine.
-	t := template.New("gotpl")
-	if e.Strict {
-		t.Option("missingkey=error")
-	} else {
-		// Not that zero will attempt to add default values for types it knows,
-		// but will still emit <no value> for others. We mitigate that later.
-		t.Option("missingkey=zero")
-	}
-
-	funcMap := e.alterFuncMap(t)
-
-	files := []string{}
+	files := make([]string, 0, len(tpls))
+	for fname := range tpls {
+		files = append(files, fname)
+	}
+	sort.Strings(files)
+
+	charts := make(map[string]string, len(tpls))
 	for fname, r := range tpls {
-		t = t.New(fname).Funcs(funcMap)
-		if _, err := t.Parse(r.tpl); err != nil {
-			return map[string]string{}, fmt.Errorf("parse error in %q: %s", fname, err)
+		charts[fname] = r.chart
+	}
+
+	// Group the files by chart.
+	byChart := map[string][]string{}
+	ids := []string{}
+	for _, fname := range files {
+		id := tpls[fname].chart
+		if _, ok := byChart[id]; !ok {
+			ids = append(ids, id)
 		}
-		files = append(files, fname)
+		byChart[id] = append(byChart[id], fname)
+	}
+
+	b := e.newBudget()
+	calls := &callErrors{}
+	namespaces := make(map[string]*namespace, len(ids))
+	for _, id := range ids {
+		t, err := e.newTemplate()
+		if err != nil {
+			return map[string]string{}, err
+		}
+		ns := &namespace{
+			id:        id,
+			ambiguous: map[string][]string{},
+			budget:    b,
+			calls:     calls,
+			t:         t,
+		}
+		if e.Strict {
+			ns.t.Option("missingkey=error")
+		} else {
+			// Not that zero will attempt to add default values for types it knows,
+			// but will still emit <no value> for others. We mitigate that later.
+			ns.t.Option("missingkey=zero")
+		}
+		ns.t.Funcs(e.namespaceFuncs(ns))
+
+		p, err := e.parse(ns.t, byChart[id], tpls, charts)
+		if err != nil {
+			return map[string]string{}, err
+		}
+		ns.files, ns.defines = p.files, p.defines
+		namespaces[id] = ns
+	}
+
+	if err := link(namespaces, ids); err != nil {
+		return map[string]string{}, err
 	}
 
 	rendered := make(map[string]string, len(files))
@@ -158,8 +333,8 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 		// At render time, add information about the template that is being rendered.
 		vals := tpls[file].vals
 		vals["Template"] = map[string]interface{}{"Name": file}
-		if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
-			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, err)
+		if err := namespaces[tpls[file].chart].t.ExecuteTemplate(b.writer(&buf), file, vals); err != nil {
+			return map[string]string{}, newRenderError(file, err, false, charts, *calls)
 		}
 
 		// Work around the issue where Go will emit "<no value>" even if Options(missing=zero)
@@ -172,6 +347,153 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 	return rendered, nil
 }
 
+// newTemplate returns an empty template set with the Engine's functions.
+func (e *Engine) newTemplate() (*template.Template, error) {
+	e.baseOnce.Do(func() {
+		// The context-specific functions are replaced in each clone.
+		e.base = template.New("").Funcs(e.alterFuncMap(&namespace{}))
+	})
+	return e.base.Clone()
+}
+
+// parsed holds the parse trees of the template files of one chart.
+//
+// The trees are not modified once parsed, so they may be shared by renders.
+type parsed struct {
+	// files maps each template file to its parse tree.
+	files map[string]*parse.Tree
+	// defines maps each named template to its parse tree.
+	defines map[string]*parse.Tree
+}
+
+// parse parses the given template files of one chart into set, which must
+// be empty. If the Engine has a Cache, trees parsed by an earlier render of
+// the same files are reused instead, and set is left empty.
+//
+// The trees are instrumented to check the budget of the render as they are
+// executed.
+func (e *Engine) parse(set *template.Template, files []string, tpls map[string]renderable, charts map[string]string) (*parsed, error) {
+	var key string
+	if e.Cache != nil {
+		key = digest(files, tpls)
+		if p, ok := e.Cache.get(key); ok {
+			return p, nil
+		}
+	}
+
+	p := &parsed{
+		files:   map[string]*parse.Tree{},
+		defines: map[string]*parse.Tree{},
+	}
+	// definedIn records the file that defines each named template.
+	definedIn := map[string]string{}
+	// A tree that has not been seen before was parsed from the current file,
+	// which makes it possible to detect a redefinition instead of silently
+	// replacing the earlier definition.
+	seen := map[*parse.Tree]bool{}
+	for _, fname := range files {
+		if _, err := set.New(fname).Parse(tpls[fname].tpl); err != nil {
+			return nil, newRenderError(fname, err, true, charts, nil)
+		}
+		for _, tt := range set.Templates() {
+			name := tt.Name()
+			if tt.Tree == nil || seen[tt.Tree] {
+				continue
+			}
+			seen[tt.Tree] = true
+			instrument(tt.Tree.Root, false)
+			if name == fname {
+				p.files[name] = tt.Tree
+				continue
+			}
+			if other, ok := definedIn[name]; ok {
+				return nil, fmt.Errorf("template %q is defined in both %q and %q", name, other, fname)
+			}
+			definedIn[name] = fname
+			p.defines[name] = tt.Tree
+		}
+	}
+
+	if e.Cache != nil {
+		e.Cache.add(key, p)
+	}
+	return p, nil
+}
+
+// link adds to the template set of each chart the templates it can reference.
+//
+// These are the chart's own template files, the named templates of every
+// chart qualified with the chart name, the named templates that exactly one
+// other chart defines, and the chart's own named templates. If several charts
+// have the same name, qualified names refer to the chart itself or else to
+// the least deeply nested of them. A name that several other charts define is
+// added as a placeholder that fails, so that it is reported as ambiguous
+// whether it is used with include or template.
+func link(namespaces map[string]*namespace, ids []string) error {
+	// definers lists the charts that define each named template.
+	definers := map[string][]*namespace{}
+	// byName maps each chart name to the chart that qualified names refer to
+	// by default.
+	byName := map[string]*namespace{}
+	for _, id := range ids {
+		ns := namespaces[id]
+		for name := range ns.defines {
+			definers[name] = append(definers[name], ns)
+		}
+		if prev, ok := byName[ns.name()]; !ok || ns.depth() < prev.depth() {
+			byName[ns.name()] = ns
+		}
+	}
+
+	for _, id := range ids {
+		ns := namespaces[id]
+		add := func(name string, tree *parse.Tree) error {
+			_, err := ns.t.AddParseTree(name, tree)
+			return err
+		}
+
+		for fname, tree := range ns.files {
+			if err := add(fname, tree); err != nil {
+				return err
+			}
+		}
+		for cname, other := range byName {
+			if cname == ns.name() {
+				other = ns
+			}
+			for name, tree := range other.defines {
+				if err := add(cname+":"+name, tree); err != nil {
+					return err
+				}
+			}
+		}
+		for name, charts := range definers {
+			if _, ok := ns.defines[name]; ok {
+				continue
+			}
+			if len(charts) == 1 {
+				if err := add(name, charts[0].defines[name]); err != nil {
+					return err
+				}
+				continue
+			}
+			for _, other := range charts {
+				ns.ambiguous[name] = append(ns.ambiguous[name], other.id)
+			}
+			// include reports the name as ambiguous.
+			if _, err := ns.t.New(name).Parse(fmt.Sprintf("{{include %q .}}", name)); err != nil {
+				return err
+			}
+		}
+		for name, tree := range ns.defines {
+			if err := add(name, tree); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // allTemplates returns all templates for a chart and its dependencies.
 //
 // As it goes, it also prepares the values in a scope-sensitive manner.
@@ -204,10 +526,11 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals char
 		}
 
 		cvals = map[string]interface{}{
-			"Values":  newVals,
-			"Release": parentVals["Release"],
-			"Chart":   c.Metadata,
-			"Files":   chartutil.NewFiles(c.Files),
+			"Values":       newVals,
+			"Release":      parentVals["Release"],
+			"Chart":        c.Metadata,
+			"Files":        chartutil.NewFiles(c.Files),
+			"Capabilities": parentVals["Capabilities"],
 		}
 	}
 
@@ -224,8 +547,9 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals char
 	}
 	for _, t := range c.Templates {
 		templates[path.Join(newParentID, t.Name)] = renderable{
-			tpl:  string(t.Data),
-			vals: cvals,
+			tpl:   string(t.Data),
+			vals:  cvals,
+			chart: newParentID,
 		}
 	}
 }
@@ -18,8 +18,10 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
@@ -405,3 +407,735 @@ func TestAlterFuncMap(t *testing.T) {
 		t.Errorf("Expected %q, got %q (%v)", expect, got, out)
 	}
 }
+
+func TestIncludeError(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "conrad"},
+		Templates: []*chart.Template{
+			{Name: "quote", Data: []byte(`{{include "conrad/_partial" .}}`)},
+			{Name: "_partial", Data: []byte(`{{.Release.Name | required "no name"}}`)},
+			{Name: "missing", Data: []byte(`{{include "conrad/_missing" .}}`)},
+		},
+	}
+
+	v := chartutil.Values{"Release": chartutil.Values{}}
+	_, err := New().Render(c, v)
+	if err == nil {
+		t.Fatal("Expected an error from include")
+	}
+	if !strings.Contains(err.Error(), "conrad/") {
+		t.Errorf("Expected the error to name the template, got %q", err)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	tests := []struct {
+		tpl    string
+		vals   chartutil.Values
+		expect string
+		err    string
+	}{
+		{`{{required "name is required" .Values.name}}`, chartutil.Values{"name": "kurtz"}, "kurtz", ""},
+		{`{{required "name is required" .Values.name}}`, chartutil.Values{}, "", "name is required"},
+		{`{{required "name is required" .Values.name}}`, chartutil.Values{"name": ""}, "", "name is required"},
+		{`{{required "port is required" .Values.port}}`, chartutil.Values{"port": 0}, "0", ""},
+	}
+
+	for _, tt := range tests {
+		c := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "conrad"},
+			Templates: []*chart.Template{{Name: "required", Data: []byte(tt.tpl)}},
+		}
+		out, err := New().Render(c, chartutil.Values{"Values": tt.vals})
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%s: expected error %q, got %v", tt.tpl, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.tpl, err)
+			continue
+		}
+		if got := out["conrad/required"]; got != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.tpl, tt.expect, got)
+		}
+	}
+}
+
+func TestTpl(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "conrad"},
+		Templates: []*chart.Template{
+			{Name: "tpl", Data: []byte(`{{tpl .Values.greeting .}}`)},
+			{Name: "_partial", Data: []byte(`{{define "conrad.name"}}{{.Release.Name}}{{end}}`)},
+			{Name: "broken", Data: []byte(`{{tpl .Values.broken .}}`)},
+		},
+	}
+	v := chartutil.Values{
+		"Values": chartutil.Values{
+			"greeting": `hello {{.Release.Name}}, {{include "conrad.name" .}}`,
+			"broken":   `{{.Release.Name`,
+		},
+		"Release": chartutil.Values{"Name": "Mistah Kurtz"},
+	}
+
+	_, err := New().Render(c, v)
+	if err == nil || !strings.Contains(err.Error(), "conrad/broken") {
+		t.Errorf("Expected a parse error from conrad/broken, got %v", err)
+	}
+
+	c.Templates = c.Templates[:2]
+	out, err := New().Render(c, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "hello Mistah Kurtz, Mistah Kurtz"
+	if got := out["conrad/tpl"]; got != expect {
+		t.Errorf("Expected %q, got %q", expect, got)
+	}
+}
+
+func TestRenderCapabilities(t *testing.T) {
+	tpl := `{{if .Capabilities.APIVersions.Has "batch/v2alpha1"}}CronJob{{else}}Job{{end}}`
+	c := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "parent"},
+		Templates: []*chart.Template{{Name: "job", Data: []byte(tpl)}},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata:  &chart.Metadata{Name: "child"},
+				Templates: []*chart.Template{{Name: "job", Data: []byte(tpl)}},
+			},
+		},
+	}
+
+	for expect, vs := range map[string]chartutil.VersionSet{
+		"CronJob": chartutil.NewVersionSet("v1", "batch/v2alpha1"),
+		"Job":     chartutil.DefaultVersionSet,
+	} {
+		v := chartutil.Values{
+			"Values":       chartutil.Values{},
+			"Capabilities": &chartutil.Capabilities{APIVersions: vs},
+		}
+		out, err := New().Render(c, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range []string{"parent/job", "parent/charts/child/job"} {
+			if got := out[name]; got != expect {
+				t.Errorf("Expected %s to render %q, got %q", name, expect, got)
+			}
+		}
+	}
+}
+
+func TestRenderNamespacedTemplates(t *testing.T) {
+	helpers := func(name string) *chart.Template {
+		return &chart.Template{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}` + name + `{{end}}`)}
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			helpers("ship"),
+			{Name: "templates/own", Data: []byte(`{{include "fullname" .}}`)},
+			{Name: "templates/qualified", Data: []byte(`{{include "captain:fullname" .}} {{template "stowaway:fullname"}}`)},
+		},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata: &chart.Metadata{Name: "captain"},
+				Templates: []*chart.Template{
+					helpers("captain"),
+					{Name: "templates/own", Data: []byte(`{{template "fullname"}}`)},
+				},
+			},
+			{
+				Metadata: &chart.Metadata{Name: "stowaway"},
+				Templates: []*chart.Template{
+					helpers("stowaway"),
+					{Name: "templates/own", Data: []byte(`{{include "fullname" .}}`)},
+				},
+			},
+		},
+	}
+
+	// Render repeatedly, since map ordering used to decide which definition won.
+	for i := 0; i < 10; i++ {
+		out, err := New().Render(ch, chartutil.Values{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := map[string]string{
+			"ship/templates/own":                 "ship",
+			"ship/templates/qualified":           "captain stowaway",
+			"ship/charts/captain/templates/own":  "captain",
+			"ship/charts/stowaway/templates/own": "stowaway",
+		}
+		for file, e := range expect {
+			if out[file] != e {
+				t.Errorf("Expected %q in %s, got %q", e, file, out[file])
+			}
+		}
+	}
+}
+
+func TestRenderAmbiguousTemplate(t *testing.T) {
+	for _, tpl := range []string{`{{include "fullname" .}}`, `{{template "fullname" .}}`} {
+		ch := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "ship"},
+			Templates: []*chart.Template{
+				{Name: "templates/name", Data: []byte(tpl)},
+			},
+			Dependencies: []*chart.Chart{
+				{
+					Metadata:  &chart.Metadata{Name: "captain"},
+					Templates: []*chart.Template{{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}captain{{end}}`)}},
+				},
+				{
+					Metadata:  &chart.Metadata{Name: "stowaway"},
+					Templates: []*chart.Template{{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}stowaway{{end}}`)}},
+				},
+			},
+		}
+
+		_, err := New().Render(ch, chartutil.Values{})
+		if err == nil {
+			t.Errorf("Expected an error for an ambiguous template in %s", tpl)
+			continue
+		}
+		expect := `template "fullname" is defined in charts ship/charts/captain, ship/charts/stowaway; qualify it as "<chart>:fullname"`
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("Expected the error for %s to contain %q, got %q", tpl, expect, err)
+		}
+	}
+}
+
+func TestRenderRedefinedTemplate(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/a.tpl", Data: []byte(`{{define "fullname"}}a{{end}}`)},
+			{Name: "templates/b.tpl", Data: []byte(`{{define "fullname"}}b{{end}}`)},
+		},
+	}
+
+	_, err := New().Render(ch, chartutil.Values{})
+	if err == nil {
+		t.Fatal("Expected an error for a redefined template")
+	}
+	expect := `template "fullname" is defined in both "ship/templates/a.tpl" and "ship/templates/b.tpl"`
+	if err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+}
+
+func TestRenderErrorLocation(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/crew.yaml", Data: []byte("crew:\n  {{include \"stowaway:name\" .}}")},
+		},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata:  &chart.Metadata{Name: "stowaway"},
+				Templates: []*chart.Template{{Name: "templates/_helpers.tpl", Data: []byte("\n{{define \"name\"}}{{.Values.swimmer.name}}{{end}}")}},
+			},
+		},
+	}
+
+	_, err := New().Render(ch, chartutil.Values{"Values": chartutil.Values{}})
+	re, ok := err.(*RenderError)
+	if !ok {
+		t.Fatalf("Expected a *RenderError, got %#v", err)
+	}
+	if re.Chart != "ship/charts/stowaway" || re.Template != "ship/charts/stowaway/templates/_helpers.tpl" {
+		t.Errorf("Expected the error in the stowaway helpers, got %q in %q", re.Template, re.Chart)
+	}
+	if re.Line != 2 || re.Column != 26 {
+		t.Errorf("Expected the error at 2:26, got %d:%d", re.Line, re.Column)
+	}
+	if len(re.Stack) != 2 {
+		t.Fatalf("Expected two frames, got %v", re.Stack)
+	}
+	if f := re.Stack[0]; f.Template != "ship/templates/crew.yaml" || f.Line != 2 || f.Action != `include "stowaway:name" .` {
+		t.Errorf("Unexpected caller frame %+v", f)
+	}
+	if f := re.Stack[1]; f.Name != "stowaway:name" || f.Action != ".Values.swimmer.name" {
+		t.Errorf("Unexpected failing frame %+v", f)
+	}
+	expect := `render error in "ship/charts/stowaway/templates/_helpers.tpl:2:26": executing "stowaway:name" at <.Values.swimmer.name>: nil pointer evaluating interface {}.name (from "ship/templates/crew.yaml:2:4" at <include "stowaway:name" .>)`
+	if err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+}
+
+func TestRenderErrorHostileMessage(t *testing.T) {
+	spoof := `template: ship/templates/b:9:9: executing "b" at <x>: error calling include: template: ship/templates/b:7: boom`
+	tests := []struct {
+		name, tpl string
+		file      string
+		line      int
+		stack     int
+	}{
+		{"fail", `{{fail .Values.msg}}`, "ship/templates/a", 1, 1},
+		{"required", "\n{{required .Values.msg .Values.missing}}", "ship/templates/a", 2, 1},
+		{"include", `{{include "helper" .}}`, "ship/templates/_helpers.tpl", 3, 2},
+		{"tpl", `{{tpl "{{fail .Values.msg}}" .}}`, "tpl", 1, 2},
+	}
+	for _, tt := range tests {
+		ch := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "ship"},
+			Templates: []*chart.Template{
+				{Name: "templates/a", Data: []byte(tt.tpl)},
+				{Name: "templates/b", Data: []byte("{{/* b */}}")},
+				{Name: "templates/_helpers.tpl", Data: []byte("\n\n{{define \"helper\"}}{{fail .Values.msg}}{{end}}")},
+			},
+		}
+
+		_, err := New().Render(ch, chartutil.Values{"Values": chartutil.Values{"msg": spoof}})
+		re, ok := err.(*RenderError)
+		if !ok {
+			t.Fatalf("%s: expected a *RenderError, got %#v", tt.name, err)
+		}
+		if re.Template != tt.file || re.Line != tt.line {
+			t.Errorf("%s: expected the error at %s:%d, got %s:%d", tt.name, tt.file, tt.line, re.Template, re.Line)
+		}
+		if len(re.Stack) != tt.stack {
+			t.Errorf("%s: expected %d frames, got %+v", tt.name, tt.stack, re.Stack)
+		}
+		if !strings.HasSuffix(re.Message, spoof) {
+			t.Errorf("%s: expected the message to end with the spoofed text, got %q", tt.name, re.Message)
+		}
+	}
+}
+
+func TestRenderErrorTemplateNameWithColon(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/odd:1: name.yaml", Data: []byte("\n{{.Values.a.b}}")},
+		},
+	}
+
+	_, err := New().Render(ch, chartutil.Values{"Values": chartutil.Values{}})
+	re, ok := err.(*RenderError)
+	if !ok {
+		t.Fatalf("Expected a *RenderError, got %#v", err)
+	}
+	if re.Template != "ship/templates/odd:1: name.yaml" || re.Line != 2 || re.Column != 9 {
+		t.Errorf("Expected the error at ship/templates/odd:1: name.yaml:2:9, got %s:%d:%d", re.Template, re.Line, re.Column)
+	}
+	if re.Chart != "ship" {
+		t.Errorf("Expected the error in chart ship, got %q", re.Chart)
+	}
+}
+
+func TestParseErrorLocation(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/crew.yaml", Data: []byte("crew:\n\n  {{if}}{{end}}")},
+		},
+	}
+
+	_, err := New().Render(ch, chartutil.Values{})
+	re, ok := err.(*RenderError)
+	if !ok {
+		t.Fatalf("Expected a *RenderError, got %#v", err)
+	}
+	if re.Template != "ship/templates/crew.yaml" || re.Line != 3 {
+		t.Errorf("Expected the error at ship/templates/crew.yaml:3, got %s:%d", re.Template, re.Line)
+	}
+	expect := `parse error in "ship/templates/crew.yaml:3": missing value for if`
+	if err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+}
+
+func TestRenderMaxIncludeDepth(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/loop", Data: []byte(`{{define "loop"}}{{include "loop" .}}{{end}}{{include "loop" .}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxIncludeDepth = 10
+	_, err := e.Render(ch, chartutil.Values{})
+	if err == nil {
+		t.Fatal("Expected an error for runaway recursion")
+	}
+	if !strings.Contains(err.Error(), "include depth exceeds the limit of 10") {
+		t.Errorf("Expected an include depth error, got %q", err)
+	}
+	if re, ok := err.(*RenderError); !ok || len(re.Stack) != 11 {
+		t.Errorf("Expected a stack of 11 frames, got %#v", err)
+	}
+}
+
+func TestRenderMaxOutputSize(t *testing.T) {
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/a", Data: []byte(strings.Repeat("0", 60))},
+			{Name: "templates/b", Data: []byte(strings.Repeat("1", 60))},
+			{Name: "templates/c", Data: []byte(`{{define "c"}}` + strings.Repeat("2", 200) + `{{end}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxOutputSize = 100
+	_, err := e.Render(ch, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "rendered output exceeds the limit of 100 bytes") {
+		t.Errorf("Expected an output size error, got %v", err)
+	}
+
+	ch.Templates = []*chart.Template{ch.Templates[2], {Name: "templates/d", Data: []byte(`{{include "c" . | trunc 10}}`)}}
+	_, err = e.Render(ch, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "rendered output exceeds the limit of 100 bytes") {
+		t.Errorf("Expected an output size error from include, got %v", err)
+	}
+}
+
+func TestRenderMaxRenderTime(t *testing.T) {
+	// Each call includes itself ten times, until it is nested seven deep.
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/loop", Data: []byte(`{{define "loop"}}{{if lt . 7}}{{range until 10}}{{include "loop" (add1 $)}}{{end}}{{end}}{{end}}{{include "loop" 0}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxRenderTime = 10 * time.Millisecond
+	start := time.Now()
+	_, err := e.Render(ch, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "rendering exceeded the time limit of 10ms") {
+		t.Errorf("Expected a time limit error, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected the render to be cancelled, took %s", d)
+	}
+}
+
+func TestRenderMaxRenderTimeWithoutOutput(t *testing.T) {
+	// A billion iterations that neither write output nor call include.
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/loop", Data: []byte(`{{range until 1000}}{{range until 1000}}{{range until 1000}}{{end}}{{end}}{{end}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxRenderTime = 10 * time.Millisecond
+	start := time.Now()
+	_, err := e.Render(ch, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "rendering exceeded the time limit of 10ms") {
+		t.Errorf("Expected a time limit error, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected the render to be cancelled, took %s", d)
+	}
+}
+
+func TestRenderMaxOutputSizeWithoutOutput(t *testing.T) {
+	tests := []string{
+		`{{$x := repeat 200000000 "a"}}`,
+		`{{range until 30000000}}{{end}}`,
+		`{{range untilStep -30000000 30000000 2}}{{end}}`,
+		`{{range until 1000}}{{range until 1000}}{{end}}{{end}}`,
+		`{{$x := randAlpha 200000000}}`,
+	}
+	for _, tpl := range tests {
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "ship"},
+			Templates: []*chart.Template{{Name: "templates/a", Data: []byte(tpl)}},
+		}
+
+		e := New()
+		e.MaxOutputSize = 1 << 20
+		_, err := e.Render(ch, chartutil.Values{})
+		if err == nil || !strings.Contains(err.Error(), "template functions allocate more than the limit of 1048576 bytes") {
+			t.Errorf("Expected an allocation limit error for %s, got %v", tpl, err)
+		}
+	}
+}
+
+func TestRenderMaxRenderTimeWithoutFunctions(t *testing.T) {
+	// A billion iterations that call no function at all.
+	items := make([]interface{}, 1000)
+	vals := chartutil.Values{"Values": chartutil.Values{"items": items}}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/loop", Data: []byte(`{{$v := .Values.items}}{{range $v}}{{range $v}}{{range $v}}{{end}}{{end}}{{end}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxRenderTime = 10 * time.Millisecond
+	start := time.Now()
+	_, err := e.Render(ch, vals)
+	if err == nil || !strings.Contains(err.Error(), "rendering exceeded the time limit of 10ms") {
+		t.Errorf("Expected a time limit error, got %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Expected the render to be cancelled, took %s", d)
+	}
+}
+
+func TestRenderMaxOutputSizeInVariables(t *testing.T) {
+	tests := []string{
+		`{{$s := "ab"}}{{range until 40}}{{$s = cat $s $s}}{{end}}`,
+		`{{$s := "ab"}}{{range until 40}}{{$s = printf "%s%s" $s $s}}{{end}}`,
+		`{{$l := list}}{{range until 100000}}{{$l = append $l 1}}{{end}}`,
+		`{{$s := "ab"}}{{range until 40}}{{$s = tpl "{{.}}{{.}}" $s}}{{end}}{{$s | trunc 1}}`,
+	}
+	for _, tpl := range tests {
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "ship"},
+			Templates: []*chart.Template{{Name: "templates/a", Data: []byte(tpl)}},
+		}
+
+		e := New()
+		e.MaxOutputSize = 1 << 20
+		_, err := e.Render(ch, chartutil.Values{})
+		if err == nil || !strings.Contains(err.Error(), "exceeds the limit of 1048576 bytes") && !strings.Contains(err.Error(), "allocate more than the limit of 1048576 bytes") {
+			t.Errorf("Expected a size limit error for %s, got %v", tpl, err)
+		}
+	}
+}
+
+func TestRenderMaxOutputSizeNestedIncludes(t *testing.T) {
+	// Both includes write 60 bytes, so the buffers of the calls in progress
+	// hold 120 bytes, though neither holds more than 100.
+	pad := strings.Repeat("0", 60)
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "inner"}}` + pad + `{{end}}{{define "outer"}}` + pad + `{{$x := include "inner" .}}{{end}}`)},
+			{Name: "templates/a", Data: []byte(`{{$x := include "outer" .}}`)},
+		},
+	}
+
+	e := New()
+	e.MaxOutputSize = 100
+	_, err := e.Render(ch, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "rendered output exceeds the limit of 100 bytes") {
+		t.Errorf("Expected an output size error, got %v", err)
+	}
+
+	// The output of calls that have returned no longer counts.
+	ch.Templates[1].Data = []byte(`{{$x := include "inner" .}}{{$y := include "inner" .}}{{include "inner" . | trunc 10}}`)
+	if _, err := e.Render(ch, chartutil.Values{}); err != nil {
+		t.Errorf("Expected the render to succeed, got %s", err)
+	}
+}
+
+func TestRenderCache(t *testing.T) {
+	e := New()
+	e.Cache = NewCache(10)
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "name"}}{{.Values.name}}{{end}}`)},
+			{Name: "templates/crew", Data: []byte(`{{include "name" .}}`)},
+		},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata:  &chart.Metadata{Name: "stowaway"},
+				Templates: []*chart.Template{{Name: "templates/crew", Data: []byte(`{{.Values.name}}`)}},
+			},
+		},
+	}
+	vals := func(name string) chartutil.Values {
+		return chartutil.Values{"Values": chartutil.Values{"name": name, "stowaway": map[string]interface{}{"name": "Legatt"}}}
+	}
+
+	for _, name := range []string{"Captain", "Mate"} {
+		out, err := e.Render(ch, vals(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out["ship/templates/crew"] != name || out["ship/charts/stowaway/templates/crew"] != "Legatt" {
+			t.Errorf("Unexpected output %v", out)
+		}
+	}
+	if e.Cache.Len() != 2 {
+		t.Errorf("Expected two cached charts, got %d", e.Cache.Len())
+	}
+
+	// Changed templates are parsed again.
+	ch.Templates[0].Data = []byte(`{{define "name"}}{{.Values.name | upper}}{{end}}`)
+	out, err := e.Render(ch, vals("Captain"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out["ship/templates/crew"] != "CAPTAIN" {
+		t.Errorf("Expected CAPTAIN, got %q", out["ship/templates/crew"])
+	}
+	if e.Cache.Len() != 3 {
+		t.Errorf("Expected three cached charts, got %d", e.Cache.Len())
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := NewCache(2)
+	c.add("a", &parsed{})
+	c.add("b", &parsed{})
+	c.get("a")
+	c.add("c", &parsed{})
+
+	if _, ok := c.get("b"); ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+	if _, ok := c.get("a"); !ok {
+		t.Error("Expected a recently used entry to be kept")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Expected two entries, got %d", c.Len())
+	}
+}
+
+func TestParallelRenderCache(t *testing.T) {
+	// Renders sharing cached templates must not see each other's values.
+	e := New()
+	e.Cache = NewCache(10)
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "moby"},
+		Templates: []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "val"}}{{.Values.val}}{{end}}`)},
+			{Name: "templates/val", Data: []byte(`{{include "val" .}}`)},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tt := fmt.Sprintf("expect-%d", i)
+			out, err := e.Render(c, chartutil.Values{"Values": chartutil.Values{"val": tt}})
+			if err != nil {
+				t.Errorf("Failed to render %s: %s", tt, err)
+			}
+			if out["moby/templates/val"] != tt {
+				t.Errorf("Expected %q, got %q", tt, out["moby/templates/val"])
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// benchmarkChart returns a chart with the given number of subcharts, each of
+// which has a few templates that use named templates.
+func benchmarkChart(subcharts int) *chart.Chart {
+	templates := func() []*chart.Template {
+		tpls := []*chart.Template{
+			{Name: "templates/_helpers.tpl", Data: []byte(`{{define "fullname"}}{{printf "%s-%s" .Release.Name .Chart.Name | trunc 63}}{{end}}`)},
+		}
+		for i := 0; i < 10; i++ {
+			tpls = append(tpls, &chart.Template{
+				Name: fmt.Sprintf("templates/svc%d.yaml", i),
+				Data: []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: {{template "fullname" .}}
+  labels:
+    chart: "{{.Chart.Name}}-{{.Chart.Version}}"
+    release: {{.Release.Name | quote}}
+spec:
+  ports:
+  {{- range $i, $p := .Values.ports}}
+  - port: {{$p}}
+    name: {{printf "port-%d" $i | quote}}
+  {{- end}}
+  selector:
+    app: {{include "fullname" . | quote}}`),
+			})
+		}
+		return tpls
+	}
+
+	c := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "parent", Version: "0.1.0"},
+		Templates: templates(),
+	}
+	for i := 0; i < subcharts; i++ {
+		c.Dependencies = append(c.Dependencies, &chart.Chart{
+			Metadata:  &chart.Metadata{Name: fmt.Sprintf("sub%d", i), Version: "0.1.0"},
+			Templates: templates(),
+		})
+	}
+	return c
+}
+
+// benchmarkRender renders benchmarkChart with the engines returned by
+// newEngine, which is called for every render if fresh is set.
+func benchmarkRender(b *testing.B, newEngine func() *Engine, fresh bool) {
+	c := benchmarkChart(20)
+	vals := chartutil.Values{
+		"Chart":   c.Metadata,
+		"Release": chartutil.Values{"Name": "bench"},
+		"Values":  chartutil.Values{"ports": []interface{}{80, 443}},
+	}
+	e := newEngine()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if fresh {
+			e = newEngine()
+		}
+		if _, err := e.Render(c, vals); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func uncachedEngine() *Engine { return New() }
+
+func cachedEngine() *Engine {
+	e := New()
+	e.Cache = NewCache(100)
+	return e
+}
+
+// BenchmarkRenderFresh is the baseline for BenchmarkRenderCached: like the
+// engine before the Cache was added, every template is parsed and the
+// functions are set up on each render.
+func BenchmarkRenderFresh(b *testing.B)  { benchmarkRender(b, uncachedEngine, true) }
+func BenchmarkRender(b *testing.B)       { benchmarkRender(b, uncachedEngine, false) }
+func BenchmarkRenderCached(b *testing.B) { benchmarkRender(b, cachedEngine, false) }
+
+func TestRaw(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{
+			{Name: "templates/svc.yaml", Data: []byte("name: {{not a template}}")},
+		},
+		Dependencies: []*chart.Chart{
+			{
+				Metadata:  &chart.Metadata{Name: "boat"},
+				Templates: []*chart.Template{{Name: "templates/svc.yaml", Data: []byte("kind: Service")}},
+			},
+		},
+	}
+
+	out, err := Raw{}.Render(c, chartutil.Values{"Values": chartutil.Values{"a": "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{
+		"ship/templates/svc.yaml":             "name: {{not a template}}",
+		"ship/charts/boat/templates/svc.yaml": "kind: Service",
+	}
+	if len(out) != len(expect) {
+		t.Errorf("Expected %d files, got %v", len(expect), out)
+	}
+	for k, v := range expect {
+		if out[k] != v {
+			t.Errorf("Expected %q in %s, got %q", v, k, out[k])
+		}
+	}
+}
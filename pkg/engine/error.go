@@ -0,0 +1,265 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RenderError describes a template that failed to parse or execute.
+//
+// Failures inside named templates and subcharts are reported at the template
+// file that contains the failing action, along with the calls that led there.
+type RenderError struct {
+	// Chart is the path of the chart that Template belongs to, e.g.
+	// "parent/charts/child".
+	Chart string
+	// Template is the template file that failed.
+	Template string
+	// Line and Column locate the failure in Template. They are zero when
+	// unknown, and Column is zero for parse errors.
+	Line, Column int
+	// Stack lists the actions that led to the failure, outermost first. The
+	// last frame is the failing action, unless the failure was a parse error.
+	Stack []Frame
+	// Message describes the failure.
+	Message string
+	// parse is true if the template failed to parse.
+	parse bool
+}
+
+// maxErrorFrames is the number of callers a RenderError message lists, so that
+// runaway recursion does not produce a runaway message.
+const maxErrorFrames = 10
+
+// Frame is an action that was being executed when a template failed.
+type Frame struct {
+	// Template is the template file that contains the action.
+	Template string
+	// Line and Column locate the action in Template.
+	Line, Column int
+	// Name is the name of the template being executed, which is Template
+	// itself or a named template defined in it.
+	Name string
+	// Action is the text of the action, e.g. `include "fullname" .`.
+	Action string
+}
+
+func (f Frame) location() string {
+	return fmt.Sprintf("%s:%d:%d", f.Template, f.Line, f.Column)
+}
+
+func (e *RenderError) Error() string {
+	kind := "render"
+	if e.parse {
+		kind = "parse"
+	}
+	loc := e.Template
+	if e.Line > 0 {
+		loc += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			loc += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	msg := e.Message
+	callers := e.Stack
+	if n := len(e.Stack); n > 0 {
+		// The failing action is the last frame unless the failure was a parse
+		// error, e.g. in a string passed to tpl.
+		if last := e.Stack[n-1]; last.Template == e.Template && last.Line == e.Line && last.Column == e.Column {
+			msg = fmt.Sprintf("executing %q at <%s>: %s", last.Name, last.Action, msg)
+			callers = e.Stack[:n-1]
+		}
+	}
+	s := fmt.Sprintf("%s error in %q: %s", kind, loc, msg)
+	for i := len(callers) - 1; i >= 0; i-- {
+		if len(callers)-i > maxErrorFrames {
+			s += fmt.Sprintf(" (and %d more)", i+1)
+			break
+		}
+		s += fmt.Sprintf(" (from %q at <%s>)", callers[i].location(), callers[i].Action)
+	}
+	return s
+}
+
+// callErrors records the errors returned by the include and tpl calls of a
+// render. Any such error aborts the render, so these are the errors of the
+// calls that led to a failure, innermost first.
+type callErrors []string
+
+// record returns the include or tpl function f, recording the errors it
+// returns in c.
+func (c *callErrors) record(f func(string, interface{}) (string, error)) func(string, interface{}) (string, error) {
+	return func(s string, data interface{}) (string, error) {
+		out, err := f(s, data)
+		if err != nil {
+			*c = append(*c, err.Error())
+		}
+		return out, err
+	}
+}
+
+// newRenderError builds a RenderError for an error from parsing or executing
+// file. charts maps template files to the path of their chart, and calls are
+// the errors returned by the include and tpl calls that led to the failure.
+//
+// text/template reports the failure of a call by prefixing the error of the
+// called template with the location of the call. Only these prefixes are
+// parsed, as the rest of the error, such as the message given to fail or
+// required, may contain anything.
+func newRenderError(file string, err error, parse bool, charts map[string]string, calls callErrors) *RenderError {
+	re := &RenderError{
+		Chart:    charts[file],
+		Template: file,
+		Message:  err.Error(),
+		parse:    parse,
+	}
+
+	rest := err.Error()
+	for i := len(calls) - 1; i >= 0; i-- {
+		if !strings.HasSuffix(rest, calls[i]) {
+			break
+		}
+		f, ok := parseCallFrame(rest[:len(rest)-len(calls[i])], charts)
+		if !ok {
+			break
+		}
+		re.Stack = append(re.Stack, f)
+		re.Template, re.Line, re.Column = f.Template, f.Line, f.Column
+		rest = calls[i]
+	}
+
+	// The error of the innermost template starts with the location of the
+	// failing action.
+	if f, msg, ok := parseFrame(rest, charts); ok {
+		if f.Name != "" {
+			re.Stack = append(re.Stack, f)
+		}
+		re.Template, re.Line, re.Column = f.Template, f.Line, f.Column
+		rest = msg
+	}
+	re.Chart = charts[re.Template]
+	re.Message = strings.TrimSpace(rest)
+	return re
+}
+
+// callSuffixes end the prefix text/template adds to the error of an include
+// or tpl call.
+var callSuffixes = []string{">: error calling include: ", ">: error calling tpl: "}
+
+// parseCallFrame parses the prefix s that text/template added to the error of
+// an include or tpl call.
+func parseCallFrame(s string, charts map[string]string) (Frame, bool) {
+	f, rest, ok := parseLocation(s, charts)
+	if !ok {
+		return f, false
+	}
+	name, rest, ok := parseExecuting(rest)
+	if !ok {
+		return f, false
+	}
+	for _, suffix := range callSuffixes {
+		if strings.HasSuffix(rest, suffix) {
+			f.Name, f.Action = name, rest[:len(rest)-len(suffix)]
+			return f, true
+		}
+	}
+	return f, false
+}
+
+// parseFrame parses the location that text/template prefixes an error with,
+// returning the frame and the rest of the error. Execution errors also name
+// the template and action being executed.
+func parseFrame(s string, charts map[string]string) (Frame, string, bool) {
+	f, rest, ok := parseLocation(s, charts)
+	if !ok {
+		return f, s, false
+	}
+	name, r, ok := parseExecuting(rest)
+	if !ok {
+		return f, rest, true
+	}
+	end := strings.Index(r, ">: ")
+	if end < 0 {
+		return f, rest, true
+	}
+	f.Name, f.Action = name, r[:end]
+	return f, r[end+len(">: "):], true
+}
+
+// locationRe matches the line and column of a location.
+var locationRe = regexp.MustCompile(`^(\d+)(?::(\d+))?: `)
+
+// fallbackLocationRe matches a location in a template that is not a file of
+// the chart, such as a string passed to tpl.
+var fallbackLocationRe = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?: `)
+
+// parseLocation parses the "template: file:line:column: " prefix of s. The
+// file is matched against the template files of charts, so that names that
+// contain colons are not mistaken for locations.
+func parseLocation(s string, charts map[string]string) (Frame, string, bool) {
+	const prefix = "template: "
+	var f Frame
+	if !strings.HasPrefix(s, prefix) {
+		return f, s, false
+	}
+	s = s[len(prefix):]
+
+	file := ""
+	for name := range charts {
+		if len(name) > len(file) && strings.HasPrefix(s, name+":") && locationRe.MatchString(s[len(name)+1:]) {
+			file = name
+		}
+	}
+	if file == "" {
+		m := fallbackLocationRe.FindStringSubmatch(s)
+		if m == nil {
+			return f, s, false
+		}
+		file = m[1]
+	}
+	m := locationRe.FindStringSubmatch(s[len(file)+1:])
+	f.Template = file
+	f.Line, _ = strconv.Atoi(m[1])
+	f.Column, _ = strconv.Atoi(m[2])
+	return f, s[len(file)+1+len(m[0]):], true
+}
+
+// parseExecuting parses the `executing "name" at <` text that follows the
+// location of an execution error.
+func parseExecuting(s string) (string, string, bool) {
+	const prefix = `executing "`
+	if !strings.HasPrefix(s, prefix) {
+		return "", s, false
+	}
+	for i := len(prefix); i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			name, err := strconv.Unquote(s[len(prefix)-1 : i+1])
+			if err != nil || !strings.HasPrefix(s[i+1:], " at <") {
+				return "", s, false
+			}
+			return name, s[i+1+len(" at <"):], true
+		}
+	}
+	return "", s, false
+}
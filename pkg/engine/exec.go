@@ -0,0 +1,255 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Exec is a template engine that runs an external program to render charts.
+//
+// The program is sent an ExecRequest as JSON on its standard input. It must
+// write a JSON object that maps the names of the rendered files to their
+// contents to its standard output, naming files the way Engine does, as in
+// "parent/charts/child/templates/svc.yaml". If the program exits with an
+// error, rendering fails with what it wrote to its standard error.
+//
+// The request is only sent as JSON, though protobuf was also considered.
+// Programs can decode JSON in any language, including shell scripts, without
+// generating code from the hapi protobuf definitions, and a single encoding
+// spares programs and Tiller from having to agree on one.
+//
+// Each call to Render runs the program anew, so renders share no state. The
+// program and any processes it starts are killed if it exceeds a limit.
+// Processes that are still running when the program exits are killed too, so
+// that they cannot hold its output open.
+type Exec struct {
+	// Path is the path of the program.
+	Path string
+	// Args are the arguments the program is run with.
+	Args []string
+	// Timeout limits how long the program may run. Zero means no limit.
+	Timeout time.Duration
+	// MaxOutputSize limits, in bytes, what the program may write to its
+	// standard output. Zero means no limit.
+	MaxOutputSize int
+}
+
+// maxExecErrorSize is how much of the standard error of a program is kept.
+const maxExecErrorSize = 64 << 10
+
+// execOutputGrace is how long the output of a program is read for once the
+// program has exited. Only processes that could not be killed along with the
+// program, as on Windows, can keep the output open for that long.
+const execOutputGrace = time.Second
+
+var errOutputLimit = errors.New("output limit exceeded")
+
+// limitedBuffer is a buffer that holds at most max bytes. Once more is
+// written, it calls full and fails, or discards the rest if full is nil.
+//
+// The buffer is not embedded, as its ReadFrom method would bypass the limit.
+type limitedBuffer struct {
+	buf  bytes.Buffer
+	max  int
+	full func()
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.max > 0 && b.buf.Len()+len(p) > b.max {
+		if b.full == nil {
+			b.buf.Write(p[:b.max-b.buf.Len()])
+			return len(p), nil
+		}
+		b.full()
+		return 0, errOutputLimit
+	}
+	return b.buf.Write(p)
+}
+
+// ExecRequest is what an Exec engine sends to its program. It is encoded with
+// encoding/json, so byte slices such as the data of templates and the contents
+// of files are base64-encoded.
+type ExecRequest struct {
+	// Chart is the chart to render, including its dependencies.
+	Chart *chart.Chart `json:"chart"`
+	// Values are the values to render the chart with, as they would be
+	// passed to the top-level templates of the Go template engine: Values,
+	// Release, Chart, Files and Capabilities.
+	Values chartutil.Values `json:"values"`
+}
+
+// Render runs the program to render chrt with values.
+func (e *Exec) Render(chrt *chart.Chart, values chartutil.Values) (map[string]string, error) {
+	in, err := json.Marshal(&ExecRequest{Chart: chrt, Values: values})
+	if err != nil {
+		return nil, fmt.Errorf("engine %s: cannot encode request: %s", e.Path, err)
+	}
+
+	cmd := exec.Command(e.Path, e.Args...)
+	setProcessGroup(cmd)
+
+	var (
+		mu      sync.Mutex
+		stopErr error
+	)
+	// stop kills the program and the processes it started, so that none of
+	// them keeps its output open, and records why.
+	stop := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopErr == nil {
+			stopErr = err
+			killProcessGroup(cmd)
+		}
+	}
+
+	stdout := &limitedBuffer{max: e.MaxOutputSize, full: func() {
+		stop(fmt.Errorf("engine %s: output exceeds the limit of %d bytes", e.Path, e.MaxOutputSize))
+	}}
+	stderr := &limitedBuffer{max: maxExecErrorSize}
+
+	// The output is read through pipes of our own rather than by cmd, so that
+	// waiting for the program does not wait for the processes it started.
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("engine %s: %s", e.Path, err)
+	}
+	defer outR.Close()
+	defer outW.Close()
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("engine %s: %s", e.Path, err)
+	}
+	defer errR.Close()
+	defer errW.Close()
+	cmd.Stdout, cmd.Stderr = outW, errW
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("engine %s: %s", e.Path, err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("engine %s: %s", e.Path, err)
+	}
+	// Only the program and its children may hold the output open.
+	outW.Close()
+	errW.Close()
+
+	go func() {
+		stdin.Write(in)
+		stdin.Close()
+	}()
+	var copies sync.WaitGroup
+	copies.Add(2)
+	go func() {
+		io.Copy(stdout, outR)
+		copies.Done()
+	}()
+	go func() {
+		io.Copy(stderr, errR)
+		copies.Done()
+	}()
+
+	if e.Timeout > 0 {
+		t := time.AfterFunc(e.Timeout, func() {
+			stop(fmt.Errorf("engine %s: rendering exceeded the time limit of %s", e.Path, e.Timeout))
+		})
+		defer t.Stop()
+	}
+	err = cmd.Wait()
+
+	// The program has exited, so kill what it left running, and read the rest
+	// of its output.
+	mu.Lock()
+	killProcessGroup(cmd)
+	mu.Unlock()
+	copied := make(chan struct{})
+	go func() {
+		copies.Wait()
+		close(copied)
+	}()
+	select {
+	case <-copied:
+	case <-time.After(execOutputGrace):
+		outR.Close()
+		errR.Close()
+		<-copied
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if stopErr != nil {
+		return nil, stopErr
+	}
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.buf.String()); msg != "" {
+			return nil, fmt.Errorf("engine %s: %s", e.Path, msg)
+		}
+		return nil, fmt.Errorf("engine %s: %s", e.Path, err)
+	}
+
+	rendered := map[string]string{}
+	if err := json.Unmarshal(stdout.buf.Bytes(), &rendered); err != nil {
+		return nil, fmt.Errorf("engine %s: cannot decode output: %s", e.Path, err)
+	}
+	return rendered, nil
+}
+
+// LoadExecEngines returns an Exec engine with the given limits for each
+// executable file in dir, named after the file. Symbolic links are followed.
+// Hidden files are ignored.
+func LoadExecEngines(dir string, timeout time.Duration, maxOutputSize int) (map[string]*Exec, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	engines := map[string]*Exec{}
+	for _, fi := range infos {
+		if strings.HasPrefix(fi.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		// ReadDir does not follow symbolic links.
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.Mode().IsRegular() || fi.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		engines[fi.Name()] = &Exec{
+			Path:          path,
+			Timeout:       timeout,
+			MaxOutputSize: maxOutputSize,
+		}
+	}
+	return engines, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestLoadExecEngines(t *testing.T) {
+	engines, err := LoadExecEngines("testdata/engines", time.Second, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(engines) != 6 {
+		t.Fatalf("Expected 6 engines, got %v", engines)
+	}
+	e, ok := engines["echo"]
+	if !ok {
+		t.Fatal("Expected an echo engine")
+	}
+	if e.Path != "testdata/engines/echo" || e.Timeout != time.Second || e.MaxOutputSize != 1024 {
+		t.Errorf("Unexpected engine %+v", e)
+	}
+	// A symbolic link to an executable is an engine too.
+	if e, ok := engines["link"]; !ok || e.Path != "testdata/engines/link" {
+		t.Errorf("Expected a link engine, got %+v", e)
+	}
+}
+
+func TestExecRender(t *testing.T) {
+	c := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "ship"},
+		Templates: []*chart.Template{{Name: "templates/name", Data: []byte("{{.Chart.Name}}")}},
+	}
+	vals := chartutil.Values{"Values": chartutil.Values{"captain": "Archbold"}}
+
+	out, err := (&Exec{Path: "testdata/engines/echo"}).Render(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 1 || out["ship/templates/name"] != "ship" {
+		t.Errorf("Unexpected output %v", out)
+	}
+}
+
+func TestExecRenderWithoutTimeout(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "ship"}}
+
+	// The engine exits, but its child keeps the output open.
+	start := time.Now()
+	out, err := (&Exec{Path: "testdata/engines/linger"}).Render(c, chartutil.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 0 {
+		t.Errorf("Unexpected output %v", out)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Expected the engine's children to be stopped once it exited, took %s", d)
+	}
+}
+
+func TestExecRenderErrors(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "ship"}}
+
+	_, err := (&Exec{Path: "testdata/engines/fail"}).Render(c, chartutil.Values{})
+	if err == nil || err.Error() != "engine testdata/engines/fail: cannot render ship" {
+		t.Errorf("Expected the engine's error, got %v", err)
+	}
+
+	start := time.Now()
+	_, err = (&Exec{Path: "testdata/engines/slow", Timeout: 50 * time.Millisecond}).Render(c, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "time limit of 50ms") {
+		t.Errorf("Expected a time limit error, got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Expected the engine and its children to be stopped, took %s", d)
+	}
+
+	start = time.Now()
+	_, err = (&Exec{Path: "testdata/engines/loud", MaxOutputSize: 1024}).Render(c, chartutil.Values{})
+	if err == nil || !strings.Contains(err.Error(), "output exceeds the limit of 1024 bytes") {
+		t.Errorf("Expected an output size error, got %v", err)
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("Expected the engine to be stopped, took %s", d)
+	}
+}
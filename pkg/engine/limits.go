@@ -0,0 +1,333 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// budget tracks the resources used by one render against the Engine's limits.
+//
+// A render runs in a single goroutine, so a budget needs no locking.
+type budget struct {
+	deadline time.Time
+	maxTime  time.Duration
+	maxDepth int
+	maxSize  int
+
+	// depth is the number of include and tpl calls in progress.
+	depth int
+	// size is the number of bytes rendered so far, including the output of
+	// the include and tpl calls in progress.
+	size int
+	// allocated is the number of bytes that template functions have
+	// allocated so far.
+	allocated int
+	// err is set once a limit has been exceeded, so that every later check
+	// fails. Functions that cannot return an error report it this way.
+	err error
+}
+
+// newBudget starts a budget for a render with the Engine's limits.
+func (e *Engine) newBudget() *budget {
+	b := &budget{
+		maxTime:  e.MaxRenderTime,
+		maxDepth: e.MaxIncludeDepth,
+		maxSize:  e.MaxOutputSize,
+	}
+	if b.maxTime > 0 {
+		b.deadline = time.Now().Add(b.maxTime)
+	}
+	return b
+}
+
+// check fails once the render has run out of time, or has exceeded any
+// other limit.
+func (b *budget) check() error {
+	if b.err == nil && b.maxTime > 0 && time.Now().After(b.deadline) {
+		b.err = fmt.Errorf("rendering exceeded the time limit of %s", b.maxTime)
+	}
+	return b.err
+}
+
+// enter records the start of an include or tpl call, failing if the calls
+// nest too deeply. Each successful call must be matched by a call to leave.
+func (b *budget) enter() error {
+	if err := b.check(); err != nil {
+		return err
+	}
+	if b.maxDepth > 0 && b.depth >= b.maxDepth {
+		return fmt.Errorf("include depth exceeds the limit of %d", b.maxDepth)
+	}
+	b.depth++
+	return nil
+}
+
+// alloc records that a template function is about to allocate count items of
+// size bytes each, failing if the render has run out of time or if the items
+// allocated by the render would exceed the output size limit.
+//
+// Functions that create lists to range over call alloc, so that the number
+// of iterations is bounded.
+func (b *budget) alloc(count, size int) error {
+	if err := b.check(); err != nil {
+		return err
+	}
+	if b.maxSize <= 0 || count <= 0 || size <= 0 {
+		return nil
+	}
+	if count > (b.maxSize-b.allocated)/size {
+		b.err = fmt.Errorf("template functions allocate more than the limit of %d bytes", b.maxSize)
+		return b.err
+	}
+	b.allocated += count * size
+	return nil
+}
+
+// leave records the end of an include or tpl call.
+func (b *budget) leave() {
+	b.depth--
+}
+
+// writer returns a writer to w that fails once the render runs out of time,
+// or once the output of the render exceeds the output size limit. Everything
+// written counts towards the size of the render until release is called.
+func (b *budget) writer(w io.Writer) *budgetWriter {
+	return &budgetWriter{b: b, w: w}
+}
+
+type budgetWriter struct {
+	b *budget
+	w io.Writer
+	n int
+}
+
+func (w *budgetWriter) Write(p []byte) (int, error) {
+	if err := w.b.check(); err != nil {
+		return 0, err
+	}
+	w.n += len(p)
+	w.b.size += len(p)
+	if max := w.b.maxSize; max > 0 && w.b.size > max {
+		w.b.err = fmt.Errorf("rendered output exceeds the limit of %d bytes", max)
+		return 0, w.b.err
+	}
+	return w.w.Write(p)
+}
+
+// release stops counting the output written to w towards the size of the
+// render. It is called once the output of an include or tpl call has been
+// returned, as the output counts again if the caller writes it.
+func (w *budgetWriter) release() {
+	w.b.size -= w.n
+	w.n = 0
+}
+
+// intSize is the size in bytes of an int.
+const intSize = strconv.IntSize / 8
+
+// budgetFuncs returns funcs wrapped to charge the memory they allocate to b,
+// along with the budgetCheck function and the builtin functions that build
+// strings.
+//
+// Functions that allocate an amount of memory chosen by the template check
+// the budget before allocating. The others are charged for the strings, lists
+// and maps they return, so that values built up in variables, as in
+// {{$s = cat $s $s}}, are bounded too.
+func budgetFuncs(b *budget, funcs template.FuncMap) template.FuncMap {
+	funcMap := template.FuncMap{
+		budgetCheck: func() (string, error) {
+			return "", b.check()
+		},
+	}
+	if f, ok := funcs["until"].(func(int) []int); ok {
+		funcMap["until"] = func(count int) ([]int, error) {
+			n := count
+			if n < 0 {
+				n = -n
+			}
+			if err := b.alloc(n, intSize); err != nil {
+				return nil, err
+			}
+			return f(count), nil
+		}
+	}
+	if f, ok := funcs["untilStep"].(func(int, int, int) []int); ok {
+		funcMap["untilStep"] = func(start, stop, step int) ([]int, error) {
+			if err := b.alloc(steps(start, stop, step), intSize); err != nil {
+				return nil, err
+			}
+			return f(start, stop, step), nil
+		}
+	}
+	if f, ok := funcs["repeat"].(func(int, string) string); ok {
+		funcMap["repeat"] = func(count int, str string) (string, error) {
+			if err := b.alloc(count, len(str)); err != nil {
+				return "", err
+			}
+			return f(count, str), nil
+		}
+	}
+	for _, name := range []string{"randAlphaNum", "randAlpha", "randAscii", "randNumeric"} {
+		if f, ok := funcs[name].(func(int) string); ok {
+			funcMap[name] = func(count int) (string, error) {
+				if err := b.alloc(count, 1); err != nil {
+					return "", err
+				}
+				return f(count), nil
+			}
+		}
+	}
+	for name, f := range funcs {
+		if _, ok := funcMap[name]; !ok {
+			funcMap[name] = chargeResult(b, f)
+		}
+	}
+	builtins := template.FuncMap{
+		"print":    fmt.Sprint,
+		"printf":   fmt.Sprintf,
+		"println":  fmt.Sprintln,
+		"html":     template.HTMLEscaper,
+		"js":       template.JSEscaper,
+		"urlquery": template.URLQueryEscaper,
+	}
+	for name, f := range builtins {
+		if _, ok := funcMap[name]; !ok {
+			funcMap[name] = chargeResult(b, f)
+		}
+	}
+	return funcMap
+}
+
+// chargeResult wraps the function f to charge the size of its result to b.
+//
+// If the charge exceeds the budget, the error is returned by f if f returns
+// an error, and otherwise by the next check of the budget.
+func chargeResult(b *budget, f interface{}) interface{} {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumOut() == 0 {
+		return f
+	}
+	return reflect.MakeFunc(ft, func(args []reflect.Value) []reflect.Value {
+		var out []reflect.Value
+		if ft.IsVariadic() {
+			out = fv.CallSlice(args)
+		} else {
+			out = fv.Call(args)
+		}
+		if err := b.alloc(1, sizeOf(out[0])); err != nil && len(out) == 2 && out[1].IsNil() {
+			out[1] = reflect.ValueOf(&err).Elem()
+		}
+		return out
+	}).Interface()
+}
+
+// sizeOf estimates the memory held by the string, list or map v, not
+// counting the memory held by its elements.
+func sizeOf(v reflect.Value) int {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return 0
+		}
+		return sizeOf(v.Elem())
+	case reflect.String:
+		return v.Len()
+	case reflect.Slice, reflect.Array:
+		return v.Len() * int(v.Type().Elem().Size())
+	case reflect.Map:
+		return v.Len() * int(v.Type().Key().Size()+v.Type().Elem().Size())
+	}
+	return 0
+}
+
+// budgetCheck is the name of the function that instrument calls to check
+// the budget of the render.
+const budgetCheck = "_budget"
+
+// instrument adds a call to the budgetCheck function before each action in
+// list and the lists nested in it, and at the start of the body of range
+// loops if loop is true. This checks the budget on every loop iteration, even
+// if the loop neither writes output nor calls a function.
+func instrument(list *parse.ListNode, loop bool) {
+	if list == nil {
+		return
+	}
+	nodes := make([]parse.Node, 0, 2*len(list.Nodes)+1)
+	if loop {
+		nodes = append(nodes, checkNode(list.Position()))
+	}
+	for _, n := range list.Nodes {
+		switch n := n.(type) {
+		case *parse.ActionNode, *parse.TemplateNode:
+		case *parse.IfNode:
+			instrument(n.List, false)
+			instrument(n.ElseList, false)
+		case *parse.WithNode:
+			instrument(n.List, false)
+			instrument(n.ElseList, false)
+		case *parse.RangeNode:
+			instrument(n.List, true)
+			instrument(n.ElseList, false)
+		default:
+			nodes = append(nodes, n)
+			continue
+		}
+		nodes = append(nodes, checkNode(n.Position()), n)
+	}
+	list.Nodes = nodes
+}
+
+// checkNode returns the action {{_budget}} at pos.
+func checkNode(pos parse.Pos) *parse.ActionNode {
+	cmd := &parse.CommandNode{
+		NodeType: parse.NodeCommand,
+		Pos:      pos,
+		Args:     []parse.Node{parse.NewIdentifier(budgetCheck).SetPos(pos)},
+	}
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Pipe:     &parse.PipeNode{NodeType: parse.NodePipe, Pos: pos, Cmds: []*parse.CommandNode{cmd}},
+	}
+}
+
+// steps returns the number of values from start towards stop, exclusive, in
+// increments of step.
+func steps(start, stop, step int) int {
+	var d, s uint64
+	switch {
+	case step > 0 && stop > start:
+		d, s = uint64(stop)-uint64(start), uint64(step)
+	case step < 0 && stop < start:
+		d, s = uint64(start)-uint64(stop), -uint64(step)
+	default:
+		return 0
+	}
+	n := (d-1)/s + 1
+	if n > uint64(^uint(0)>>1) {
+		return int(^uint(0) >> 1)
+	}
+	return int(n)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// Raw is a template engine that renders every template as it is, for charts
+// whose templates are plain YAML that needs no templating.
+//
+// Like Engine, Raw names each rendered file after the path of its chart and
+// the template, as in "parent/charts/child/templates/svc.yaml".
+type Raw struct{}
+
+// Render returns the templates of chrt and its dependencies unmodified. The
+// values are ignored.
+func (Raw) Render(chrt *chart.Chart, values chartutil.Values) (map[string]string, error) {
+	tpls := allTemplates(chrt, chartutil.Values{})
+	rendered := make(map[string]string, len(tpls))
+	for fname, r := range tpls {
+		rendered[fname] = r.tpl
+	}
+	return rendered, nil
+}
@@ -18,6 +18,7 @@ package helm // import "k8s.io/helm/pkg/helm"
 
 import (
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"k8s.io/helm/pkg/chartutil"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
@@ -60,9 +61,19 @@ func (h *Client) Init() *Client {
 	return h
 }
 
+// connect returns a connection to Tiller, secured with TLS if a TLS
+// configuration was provided.
+func (h *Client) connect() (*grpc.ClientConn, error) {
+	opt := grpc.WithInsecure()
+	if h.opts.tlsConfig != nil {
+		opt = grpc.WithTransportCredentials(credentials.NewTLS(h.opts.tlsConfig))
+	}
+	return grpc.Dial(h.opts.host, opt)
+}
+
 // ListReleases lists the current releases.
 func (h *Client) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +84,7 @@ func (h *Client) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesRespo
 
 // InstallRelease installs a new chart and returns the release response.
 func (h *Client) InstallRelease(chStr, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +103,7 @@ func (h *Client) InstallRelease(chStr, ns string, opts ...InstallOption) (*rls.I
 // Note: there aren't currently any supported DeleteOptions, but they are
 // kept in the API signature as a placeholder for future additions.
 func (h *Client) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.UninstallReleaseResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -103,7 +114,7 @@ func (h *Client) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.Unins
 
 // UpdateRelease updates a release to a new/different chart
 func (h *Client) UpdateRelease(rlsName string, chStr string, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -122,7 +133,7 @@ func (h *Client) UpdateRelease(rlsName string, chStr string, opts ...UpdateOptio
 // Note: there aren't currently any supported StatusOptions,
 // but they are kept in the API signature as a placeholder for future additions.
 func (h *Client) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +144,7 @@ func (h *Client) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, err
 
 // RollbackRelease rolls back a release to the previous version
 func (h *Client) RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.RollbackReleaseResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +158,7 @@ func (h *Client) RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.R
 // Note: there aren't currently any  supported StatusOptions,
 // but they are kept in the API signature as a placeholder for future additions.
 func (h *Client) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.GetReleaseStatusResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +172,7 @@ func (h *Client) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.GetRe
 // Note: there aren't currently any supported ContentOptions, but
 // they are kept in the API signature as a placeholder for future additions.
 func (h *Client) ReleaseContent(rlsName string, opts ...ContentOption) (*rls.GetReleaseContentResponse, error) {
-	c, err := grpc.Dial(h.opts.host, grpc.WithInsecure())
+	c, err := h.connect()
 	if err != nil {
 		return nil, err
 	}
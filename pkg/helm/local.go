@@ -0,0 +1,165 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"io"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"k8s.io/helm/pkg/chartutil"
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// LocalClient manages releases in-process, by calling a release server
+// directly instead of connecting to Tiller.
+type LocalClient struct {
+	opts options
+	rlc  rls.ReleaseServiceClient
+}
+
+// NewLocalClient creates a client that calls srv directly.
+func NewLocalClient(srv rls.ReleaseServiceServer, opts ...Option) *LocalClient {
+	h := &LocalClient{rlc: &localReleaseClient{srv: srv}}
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	return h
+}
+
+// ListReleases lists the current releases.
+func (h *LocalClient) ListReleases(opts ...ReleaseListOption) (*rls.ListReleasesResponse, error) {
+	return h.opts.rpcListReleases(h.rlc, opts...)
+}
+
+// InstallRelease installs a new chart and returns the release response.
+func (h *LocalClient) InstallRelease(chStr, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
+	chart, err := chartutil.Load(chStr)
+	if err != nil {
+		return nil, err
+	}
+	return h.opts.rpcInstallRelease(chart, h.rlc, ns, opts...)
+}
+
+// DeleteRelease uninstalls a named release and returns the response.
+func (h *LocalClient) DeleteRelease(rlsName string, opts ...DeleteOption) (*rls.UninstallReleaseResponse, error) {
+	return h.opts.rpcDeleteRelease(rlsName, h.rlc, opts...)
+}
+
+// UpdateRelease updates a release to a new/different chart.
+func (h *LocalClient) UpdateRelease(rlsName string, chStr string, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error) {
+	chart, err := chartutil.Load(chStr)
+	if err != nil {
+		return nil, err
+	}
+	return h.opts.rpcUpdateRelease(rlsName, chart, h.rlc, opts...)
+}
+
+// GetVersion returns the version of the release server, which is the version
+// of this client.
+func (h *LocalClient) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error) {
+	return h.opts.rpcGetVersion(h.rlc, opts...)
+}
+
+// RollbackRelease rolls back a release to the previous version.
+func (h *LocalClient) RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.RollbackReleaseResponse, error) {
+	return h.opts.rpcRollbackRelease(rlsName, h.rlc, opts...)
+}
+
+// ReleaseStatus returns the given release's status.
+func (h *LocalClient) ReleaseStatus(rlsName string, opts ...StatusOption) (*rls.GetReleaseStatusResponse, error) {
+	return h.opts.rpcGetReleaseStatus(rlsName, h.rlc, opts...)
+}
+
+// ReleaseContent returns the configuration for a given release.
+func (h *LocalClient) ReleaseContent(rlsName string, opts ...ContentOption) (*rls.GetReleaseContentResponse, error) {
+	return h.opts.rpcGetReleaseContent(rlsName, h.rlc, opts...)
+}
+
+// localReleaseClient implements rls.ReleaseServiceClient by calling a
+// release server in the same process.
+type localReleaseClient struct {
+	srv rls.ReleaseServiceServer
+}
+
+func (c *localReleaseClient) ListReleases(ctx context.Context, in *rls.ListReleasesRequest, opts ...grpc.CallOption) (rls.ReleaseService_ListReleasesClient, error) {
+	stream := &localListStream{ctx: ctx}
+	if err := c.srv.ListReleases(in, stream); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func (c *localReleaseClient) GetReleaseStatus(ctx context.Context, in *rls.GetReleaseStatusRequest, opts ...grpc.CallOption) (*rls.GetReleaseStatusResponse, error) {
+	return c.srv.GetReleaseStatus(ctx, in)
+}
+
+func (c *localReleaseClient) GetReleaseContent(ctx context.Context, in *rls.GetReleaseContentRequest, opts ...grpc.CallOption) (*rls.GetReleaseContentResponse, error) {
+	return c.srv.GetReleaseContent(ctx, in)
+}
+
+func (c *localReleaseClient) UpdateRelease(ctx context.Context, in *rls.UpdateReleaseRequest, opts ...grpc.CallOption) (*rls.UpdateReleaseResponse, error) {
+	return c.srv.UpdateRelease(ctx, in)
+}
+
+func (c *localReleaseClient) InstallRelease(ctx context.Context, in *rls.InstallReleaseRequest, opts ...grpc.CallOption) (*rls.InstallReleaseResponse, error) {
+	return c.srv.InstallRelease(ctx, in)
+}
+
+func (c *localReleaseClient) UninstallRelease(ctx context.Context, in *rls.UninstallReleaseRequest, opts ...grpc.CallOption) (*rls.UninstallReleaseResponse, error) {
+	return c.srv.UninstallRelease(ctx, in)
+}
+
+func (c *localReleaseClient) GetVersion(ctx context.Context, in *rls.GetVersionRequest, opts ...grpc.CallOption) (*rls.GetVersionResponse, error) {
+	return c.srv.GetVersion(ctx, in)
+}
+
+func (c *localReleaseClient) RollbackRelease(ctx context.Context, in *rls.RollbackReleaseRequest, opts ...grpc.CallOption) (*rls.RollbackReleaseResponse, error) {
+	return c.srv.RollbackRelease(ctx, in)
+}
+
+// localListStream is both ends of a ListReleases stream. The server sends all
+// of its batches before the client receives the first.
+type localListStream struct {
+	ctx     context.Context
+	batches []*rls.ListReleasesResponse
+}
+
+func (s *localListStream) Send(res *rls.ListReleasesResponse) error {
+	s.batches = append(s.batches, res)
+	return nil
+}
+
+func (s *localListStream) Recv() (*rls.ListReleasesResponse, error) {
+	if len(s.batches) == 0 {
+		return nil, io.EOF
+	}
+	res := s.batches[0]
+	s.batches = s.batches[1:]
+	return res, nil
+}
+
+func (s *localListStream) Context() context.Context     { return s.ctx }
+func (s *localListStream) SendMsg(m interface{}) error  { return nil }
+func (s *localListStream) RecvMsg(m interface{}) error  { return nil }
+func (s *localListStream) SendHeader(metadata.MD) error { return nil }
+func (s *localListStream) SetTrailer(metadata.MD)       {}
+func (s *localListStream) Header() (metadata.MD, error) { return nil, nil }
+func (s *localListStream) Trailer() metadata.MD         { return nil }
+func (s *localListStream) CloseSend() error             { return nil }
@@ -0,0 +1,64 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var _ Interface = &LocalClient{}
+
+func TestLocalClient(t *testing.T) {
+	env := environment.New()
+	env.Releases = storage.Init(driver.NewMemory())
+	env.KubeClient = &environment.PrintingKubeClient{Out: ioutil.Discard}
+	h := NewLocalClient(tiller.NewReleaseServer(env), KubeUser("jane"))
+
+	res, err := h.InstallRelease("../../docs/examples/alpine", "default", ReleaseName("local"))
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if id := res.Release.Info.Identity; id.KubeUser != "jane" {
+		t.Errorf("Expected kube user jane, got %q", id.KubeUser)
+	}
+
+	list, err := h.ListReleases()
+	if err != nil {
+		t.Fatalf("Failed list: %s", err)
+	}
+	if len(list.Releases) != 1 || list.Releases[0].Name != "local" {
+		t.Errorf("Expected release local to be listed, got %v", list.Releases)
+	}
+
+	if _, err := h.DeleteRelease("local"); err != nil {
+		t.Fatalf("Failed delete: %s", err)
+	}
+	rel, err := env.Releases.Get("local", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Info.Status.Code != release.Status_DELETED {
+		t.Errorf("Expected release to be DELETED, got %s", rel.Info.Status.Code)
+	}
+}
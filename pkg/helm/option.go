@@ -17,6 +17,11 @@ limitations under the License.
 package helm
 
 import (
+	"crypto/tls"
+	"io"
+	"os"
+	"os/user"
+
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/metadata"
 
@@ -41,6 +46,10 @@ type options struct {
 	reuseName bool
 	// if set, skip running hooks
 	disableHooks bool
+	// name of the kubeconfig user reported to Tiller
+	kubeUser string
+	// if set, connect to Tiller over TLS with this configuration
+	tlsConfig *tls.Config
 	// release list options are applied directly to the list releases request
 	listReq rls.ListReleasesRequest
 	// release install options are applied directly to the install release request
@@ -64,6 +73,22 @@ func Host(host string) Option {
 	}
 }
 
+// WithTLS specifies the TLS configuration used to connect to Tiller. Without
+// it, the connection is insecure.
+func WithTLS(cfg *tls.Config) Option {
+	return func(opts *options) {
+		opts.tlsConfig = cfg
+	}
+}
+
+// KubeUser specifies the kubeconfig user the client reports to Tiller as part
+// of its identity.
+func KubeUser(user string) Option {
+	return func(opts *options) {
+		opts.kubeUser = user
+	}
+}
+
 // ReleaseListOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm list` command.
@@ -114,6 +139,36 @@ func ReleaseListStatuses(statuses []release.Status_Code) ReleaseListOption {
 	}
 }
 
+// ReleaseListNamespace restricts a release list to a single namespace.
+func ReleaseListNamespace(namespace string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.Namespace = namespace
+	}
+}
+
+// ReleaseListChart restricts a release list to releases of the named chart.
+func ReleaseListChart(name string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.ChartName = name
+	}
+}
+
+// ReleaseListChartVersion restricts a release list to releases whose chart
+// version satisfies the given SemVer range.
+func ReleaseListChartVersion(version string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.ChartVersion = version
+	}
+}
+
+// ReleaseListLabels restricts a release list to releases carrying all of the
+// given labels.
+func ReleaseListLabels(labels map[string]string) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.Labels = labels
+	}
+}
+
 // InstallOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm install` command.
@@ -182,6 +237,62 @@ func InstallReuseName(reuse bool) InstallOption {
 	}
 }
 
+// InstallDescription records why the release was installed.
+func InstallDescription(description string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Description = description
+	}
+}
+
+// InstallLabels attaches labels to the installed release.
+func InstallLabels(labels map[string]string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Labels = labels
+	}
+}
+
+// DeleteDescription records why the release was deleted.
+func DeleteDescription(description string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Description = description
+	}
+}
+
+// DeleteLabels adds labels to the deleted release.
+func DeleteLabels(labels map[string]string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Labels = labels
+	}
+}
+
+// RollbackDescription records why the release was rolled back.
+func RollbackDescription(description string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Description = description
+	}
+}
+
+// RollbackLabels adds labels to the rolled back release.
+func RollbackLabels(labels map[string]string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Labels = labels
+	}
+}
+
+// UpgradeDescription records why the release was upgraded.
+func UpgradeDescription(description string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Description = description
+	}
+}
+
+// UpgradeLabels adds labels to the upgraded release.
+func UpgradeLabels(labels map[string]string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Labels = labels
+	}
+}
+
 // RollbackDisableHooks will disable hooks for a rollback operation
 func RollbackDisableHooks(disable bool) RollbackOption {
 	return func(opts *options) {
@@ -257,17 +368,38 @@ type RollbackOption func(*options)
 // malleable.
 
 // Executes tiller.ListReleases RPC.
+//
+// Tiller may split a listing across several messages. The batches are
+// accumulated into a single response.
 func (o *options) rpcListReleases(rlc rls.ReleaseServiceClient, opts ...ReleaseListOption) (*rls.ListReleasesResponse, error) {
 	// apply release list options
 	for _, opt := range opts {
 		opt(o)
 	}
-	s, err := rlc.ListReleases(NewContext(), &o.listReq)
+	s, err := rlc.ListReleases(o.context(), &o.listReq)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.Recv()
+	var res *rls.ListReleasesResponse
+	for {
+		r, err := s.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			res = r
+			continue
+		}
+		res.Releases = append(res.Releases, r.Releases...)
+	}
+	if res == nil {
+		res = &rls.ListReleasesResponse{}
+	}
+	return res, nil
 }
 
 // NewContext creates a versioned context.
@@ -276,6 +408,32 @@ func NewContext() context.Context {
 	return metadata.NewContext(context.TODO(), md)
 }
 
+// context creates a versioned context that also carries the identity of the
+// caller, which Tiller records on each release it creates.
+func (o *options) context() context.Context {
+	md := metadata.Pairs(
+		"x-helm-api-client", version.Version,
+		"x-helm-kube-user", o.kubeUser,
+		"x-helm-os-user", osUser(),
+		"x-helm-hostname", hostname(),
+	)
+	return metadata.NewContext(context.TODO(), md)
+}
+
+// osUser returns the name of the operating system user running the client.
+func osUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// hostname returns the host name reported by the kernel.
+func hostname() string {
+	h, _ := os.Hostname()
+	return h
+}
+
 // Executes tiller.InstallRelease RPC.
 func (o *options) rpcInstallRelease(chr *cpb.Chart, rlc rls.ReleaseServiceClient, ns string, opts ...InstallOption) (*rls.InstallReleaseResponse, error) {
 	// apply the install options
@@ -288,7 +446,7 @@ func (o *options) rpcInstallRelease(chr *cpb.Chart, rlc rls.ReleaseServiceClient
 	o.instReq.DisableHooks = o.disableHooks
 	o.instReq.ReuseName = o.reuseName
 
-	return rlc.InstallRelease(NewContext(), &o.instReq)
+	return rlc.InstallRelease(o.context(), &o.instReq)
 }
 
 // Executes tiller.UninstallRelease RPC.
@@ -308,7 +466,7 @@ func (o *options) rpcDeleteRelease(rlsName string, rlc rls.ReleaseServiceClient,
 	o.uninstallReq.Name = rlsName
 	o.uninstallReq.DisableHooks = o.disableHooks
 
-	return rlc.UninstallRelease(NewContext(), &o.uninstallReq)
+	return rlc.UninstallRelease(o.context(), &o.uninstallReq)
 }
 
 // Executes tiller.UpdateRelease RPC.
@@ -321,7 +479,7 @@ func (o *options) rpcUpdateRelease(rlsName string, chr *cpb.Chart, rlc rls.Relea
 	o.updateReq.DryRun = o.dryRun
 	o.updateReq.Name = rlsName
 
-	return rlc.UpdateRelease(NewContext(), &o.updateReq)
+	return rlc.UpdateRelease(o.context(), &o.updateReq)
 }
 
 // Executes tiller.UpdateRelease RPC.
@@ -333,7 +491,7 @@ func (o *options) rpcRollbackRelease(rlsName string, rlc rls.ReleaseServiceClien
 	o.rollbackReq.DryRun = o.dryRun
 	o.rollbackReq.Name = rlsName
 
-	return rlc.RollbackRelease(context.TODO(), &o.rollbackReq)
+	return rlc.RollbackRelease(o.context(), &o.rollbackReq)
 }
 
 // Executes tiller.GetReleaseStatus RPC.
@@ -342,7 +500,7 @@ func (o *options) rpcGetReleaseStatus(rlsName string, rlc rls.ReleaseServiceClie
 		opt(o)
 	}
 	o.statusReq.Name = rlsName
-	return rlc.GetReleaseStatus(NewContext(), &o.statusReq)
+	return rlc.GetReleaseStatus(o.context(), &o.statusReq)
 }
 
 // Executes tiller.GetReleaseContent.
@@ -351,11 +509,11 @@ func (o *options) rpcGetReleaseContent(rlsName string, rlc rls.ReleaseServiceCli
 		opt(o)
 	}
 	o.contentReq.Name = rlsName
-	return rlc.GetReleaseContent(NewContext(), &o.contentReq)
+	return rlc.GetReleaseContent(o.context(), &o.contentReq)
 }
 
 // Executes tiller.GetVersion RPC.
 func (o *options) rpcGetVersion(rlc rls.ReleaseServiceClient, opts ...VersionOption) (*rls.GetVersionResponse, error) {
 	req := &rls.GetVersionRequest{}
-	return rlc.GetVersion(NewContext(), req)
+	return rlc.GetVersion(o.context(), req)
 }
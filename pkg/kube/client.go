@@ -276,15 +276,41 @@ func (c *Client) WatchUntilReady(namespace string, reader io.Reader) error {
 
 const includeThirdPartyAPIs = false
 
-func perform(c *Client, namespace string, reader io.Reader, fn ResourceActorFunc) error {
-	r := c.NewBuilder(includeThirdPartyAPIs).
+// Namespaces returns the namespace of each namespaced resource in reader, as
+// Create would create it. Resources that do not set a namespace are in
+// namespace, and the items of lists are returned individually. Cluster-scoped
+// resources are skipped.
+func (c *Client) Namespaces(namespace string, reader io.Reader) ([]string, error) {
+	r := build(c, namespace, reader)
+	if r.Err() != nil {
+		return nil, r.Err()
+	}
+	var namespaces []string
+	err := r.Visit(func(info *resource.Info, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Namespaced() {
+			namespaces = append(namespaces, info.Namespace)
+		}
+		return nil
+	})
+	return namespaces, err
+}
+
+// build decodes the resources in reader.
+func build(c *Client, namespace string, reader io.Reader) *resource.Result {
+	return c.NewBuilder(includeThirdPartyAPIs).
 		ContinueOnError().
 		NamespaceParam(namespace).
 		DefaultNamespace().
 		Stream(reader, "").
 		Flatten().
 		Do()
+}
 
+func perform(c *Client, namespace string, reader io.Reader, fn ResourceActorFunc) error {
+	r := build(c, namespace, reader)
 	if r.Err() != nil {
 		return r.Err()
 	}
@@ -434,6 +460,13 @@ func (c *Client) ensureNamespace(namespace string) error {
 	ns.Name = namespace
 	_, err = client.Namespaces().Create(ns)
 	if err != nil && !errors.IsAlreadyExists(err) {
+		if errors.IsForbidden(err) {
+			// A namespace-scoped Tiller may not be allowed to create
+			// namespaces. Let the creation of the resources report whether
+			// the namespace exists.
+			log.Printf("warning: Not allowed to create namespace %q: %s", namespace, err)
+			return nil
+		}
 		return err
 	}
 	return nil
@@ -22,6 +22,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -120,6 +121,67 @@ func TestPerform(t *testing.T) {
 	}
 }
 
+func TestNamespaces(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		expect   []string
+		err      bool
+	}{
+		{
+			name:     "default namespace",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n",
+			expect:   []string{"test"},
+		},
+		{
+			name:     "separator with trailing space",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: a\n--- \napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: b\n  namespace: kube-system\n",
+			expect:   []string{"test", "kube-system"},
+		},
+		{
+			name: "list items",
+			manifest: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+    namespace: kube-system
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: b
+`,
+			expect: []string{"kube-system", "test"},
+		},
+		{
+			name:     "cluster-scoped resource",
+			manifest: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: a\n",
+		},
+		{
+			name:     "not a resource",
+			manifest: "hello: world\n",
+			err:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		c := New(nil)
+		c.ClientForMapping = func(mapping *meta.RESTMapping) (resource.RESTClient, error) {
+			return &fake.RESTClient{}, nil
+		}
+
+		namespaces, err := c.Namespaces("test", strings.NewReader(tt.manifest))
+		if (err != nil) != tt.err {
+			t.Errorf("%q. expected error: %v, got %v", tt.name, tt.err, err)
+		}
+		if !reflect.DeepEqual(namespaces, tt.expect) {
+			t.Errorf("%q. expected namespaces %v, got %v", tt.name, tt.expect, namespaces)
+		}
+	}
+}
+
 func TestReal(t *testing.T) {
 	t.Skip("This is a live test, comment this line to run")
 	if err := New(nil).Create("test", strings.NewReader(guestbookManifest)); err != nil {
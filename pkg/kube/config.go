@@ -0,0 +1,38 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+
+	"k8s.io/kubernetes/pkg/client/unversioned/clientcmd"
+)
+
+// CurrentUser returns the name of the user of the current context in the
+// default kubeconfig.
+func CurrentUser() (string, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return "", err
+	}
+	ctx, ok := config.Contexts[config.CurrentContext]
+	if !ok {
+		return "", fmt.Errorf("context %q does not exist in kubeconfig", config.CurrentContext)
+	}
+	return ctx.AuthInfo, nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testKubeConfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: prod
+  cluster:
+    server: https://prod.example.com
+users:
+- name: alice
+  user:
+    token: secret
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: alice
+current-context: prod
+`
+
+func TestCurrentUser(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-kube-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config")
+	if err := ioutil.WriteFile(path, []byte(testKubeConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Setenv("KUBECONFIG", os.Getenv("KUBECONFIG"))
+	os.Setenv("KUBECONFIG", path)
+
+	user, err := CurrentUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user != "alice" {
+		t.Errorf("expected user alice, got %q", user)
+	}
+}
@@ -25,10 +25,17 @@ import (
 
 // All runs all of the available linters on the given base directory.
 func All(basedir string) support.Linter {
+	return AllWithValues(basedir, nil)
+}
+
+// AllWithValues runs all of the available linters on the given base
+// directory, rendering the chart with values, in YAML, overriding its
+// defaults.
+func AllWithValues(basedir string, values []byte) support.Linter {
 	// Using abs path to get directory context
 	chartDir, _ := filepath.Abs(basedir)
 
-	linter := support.Linter{ChartDir: chartDir}
+	linter := support.Linter{ChartDir: chartDir, Values: values}
 	rules.Chartfile(&linter)
 	rules.Values(&linter)
 	rules.Templates(&linter)
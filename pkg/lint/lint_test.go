@@ -28,6 +28,8 @@ const badChartDir = "rules/testdata/badchartfile"
 const badValuesFileDir = "rules/testdata/badvaluesfile"
 const badYamlFileDir = "rules/testdata/albatross"
 const goodChartDir = "rules/testdata/goodone"
+const badSchemaDir = "rules/testdata/badschema"
+const unsupportedSchemaDir = "rules/testdata/unsupportedschema"
 
 func TestBadChart(t *testing.T) {
 	m := All(badChartDir).Messages
@@ -80,6 +82,26 @@ func TestBadValues(t *testing.T) {
 	}
 }
 
+func TestBadSchema(t *testing.T) {
+	m := All(badSchemaDir).Messages
+	if len(m) != 1 {
+		t.Fatalf("All didn't fail with expected errors, got %#v", m)
+	}
+	if m[0].Path != "values.yaml" || !strings.Contains(m[0].Err.Error(), "$.replicaCont: property is not allowed") {
+		t.Errorf("All didn't have the error for the misspelled value: %s", m[0])
+	}
+}
+
+func TestUnsupportedSchema(t *testing.T) {
+	m := All(unsupportedSchemaDir).Messages
+	if len(m) != 1 {
+		t.Fatalf("All didn't fail with expected errors, got %#v", m)
+	}
+	if m[0].Path != "values.schema.json" || !strings.Contains(m[0].Err.Error(), `#/properties/replicaCount: unsupported keyword "oneOf"`) {
+		t.Errorf("All didn't have the error for the unsupported keyword: %s", m[0])
+	}
+}
+
 func TestGoodChart(t *testing.T) {
 	m := All(goodChartDir).Messages
 	if len(m) != 0 {
@@ -31,6 +31,7 @@ import (
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/lint/support"
+	cpb "k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/timeconv"
 )
 
@@ -56,7 +57,11 @@ func Templates(linter *support.Linter) {
 	}
 
 	options := chartutil.ReleaseOptions{Name: "testRelease", Time: timeconv.Now(), Namespace: "testNamespace"}
-	valuesToRender, err := chartutil.ToRenderValues(chart, chart.Values, options)
+	overrides := chart.Values
+	if linter.Values != nil {
+		overrides = &cpb.Config{Raw: string(linter.Values)}
+	}
+	valuesToRender, err := chartutil.ToRenderValues(chart, overrides, options, chartutil.DefaultCapabilities)
 	if err != nil {
 		// FIXME: This seems to generate a duplicate, but I can't find where the first
 		// error is coming from.
@@ -64,6 +69,10 @@ func Templates(linter *support.Linter) {
 		return
 	}
 	renderedContentMap, err := engine.New().Render(chart, valuesToRender)
+	if re, ok := err.(*engine.RenderError); ok {
+		// Report the error against the file that failed, relative to the chart.
+		path = strings.TrimPrefix(re.Template, chart.Metadata.Name+"/")
+	}
 
 	renderOk := linter.RunLinterRule(support.ErrorSev, path, err)
 
@@ -88,6 +88,10 @@ func TestTemplateParsing(t *testing.T) {
 	if !strings.Contains(res[0].Err.Error(), "deliberateSyntaxError") {
 		t.Errorf("Unexpected error: %s", res[0])
 	}
+
+	if res[0].Path != "templates/fail.yaml" {
+		t.Errorf("Expected the error to be reported at templates/fail.yaml, got %s", res[0].Path)
+	}
 }
 
 var wrongTemplatePath = filepath.Join(templateTestBasedir, "templates", "fail.yaml")
@@ -18,11 +18,13 @@ package rules
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
 // Values lints a chart's values.yaml file.
@@ -35,7 +37,18 @@ func Values(linter *support.Linter) {
 		return
 	}
 
-	linter.RunLinterRule(support.ErrorSev, file, validateValuesFile(linter, vf))
+	if !linter.RunLinterRule(support.ErrorSev, file, validateValuesFile(linter, vf)) {
+		return
+	}
+
+	sf := filepath.Join(linter.ChartDir, chartutil.SchemafileName)
+	if _, err := os.Stat(sf); err == nil {
+		if !linter.RunLinterRule(support.ErrorSev, chartutil.SchemafileName, validateSchemaFile(sf)) {
+			return
+		}
+	}
+
+	linter.RunLinterRule(support.ErrorSev, file, validateValuesSchema(linter))
 }
 
 func validateValuesFileExistence(linter *support.Linter, valuesPath string) error {
@@ -53,3 +66,36 @@ func validateValuesFile(linter *support.Linter, valuesPath string) error {
 	}
 	return nil
 }
+
+// validateSchemaFile checks that the schema only uses keywords that are
+// enforced.
+func validateSchemaFile(schemaPath string) error {
+	data, err := ioutil.ReadFile(schemaPath)
+	if err != nil {
+		return err
+	}
+	if err := chartutil.ValidateSchema(data); err != nil {
+		return fmt.Errorf("invalid schema\n\t%s", err)
+	}
+	return nil
+}
+
+// validateValuesSchema validates the values of the chart and its dependencies,
+// including any the linter overrides them with, against their
+// values.schema.json files.
+func validateValuesSchema(linter *support.Linter) error {
+	c, err := chartutil.Load(linter.ChartDir)
+	if err != nil {
+		// Loading errors are reported by the Templates rule.
+		return nil
+	}
+	overrides := c.Values
+	if linter.Values != nil {
+		overrides = &chart.Config{Raw: string(linter.Values)}
+	}
+	vals, err := chartutil.CoalesceValues(c, overrides)
+	if err != nil {
+		return err
+	}
+	return chartutil.ValidateAgainstSchema(c, vals)
+}
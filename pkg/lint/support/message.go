@@ -39,6 +39,8 @@ type Linter struct {
 	// The highest severity of all the failing lint rules
 	HighestSeverity int
 	ChartDir        string
+	// Values, in YAML, override the chart's default values.
+	Values []byte
 }
 
 // Message describes an error encountered while linting.
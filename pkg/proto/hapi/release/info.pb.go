@@ -21,6 +21,18 @@ type Info struct {
 	LastDeployed  *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=last_deployed,json=lastDeployed" json:"last_deployed,omitempty"`
 	// Deleted tracks when this object was deleted.
 	Deleted *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=deleted" json:"deleted,omitempty"`
+	// Description is a human-friendly description of why this revision was
+	// made, e.g. "hotfix for INC-1234".
+	Description string `protobuf:"bytes,5,opt,name=description" json:"description,omitempty"`
+	// Labels are user-supplied key/value pairs attached to the release. They
+	// are stored as storage driver labels so releases can be queried by them.
+	Labels map[string]string `protobuf:"bytes,6,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Identity is the caller that performed the operation that produced this
+	// revision.
+	Identity *Identity `protobuf:"bytes,7,opt,name=identity" json:"identity,omitempty"`
+	// Operation is the name of the RPC that produced this revision, e.g.
+	// "UpdateRelease".
+	Operation string `protobuf:"bytes,8,opt,name=operation" json:"operation,omitempty"`
 }
 
 func (m *Info) Reset()                    { *m = Info{} }
@@ -56,26 +68,70 @@ func (m *Info) GetDeleted() *google_protobuf.Timestamp {
 	return nil
 }
 
+func (m *Info) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
+func (m *Info) GetIdentity() *Identity {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+// Identity describes the caller of a Tiller RPC, as reported by the client.
+type Identity struct {
+	// KubeUser is the user of the current context in the client's kubeconfig.
+	KubeUser string `protobuf:"bytes,1,opt,name=kube_user,json=kubeUser" json:"kube_user,omitempty"`
+	// OsUser is the operating system user running the client.
+	OsUser string `protobuf:"bytes,2,opt,name=os_user,json=osUser" json:"os_user,omitempty"`
+	// Hostname is the name of the host the client runs on.
+	Hostname string `protobuf:"bytes,3,opt,name=hostname" json:"hostname,omitempty"`
+	// ClientVersion is the version of the client.
+	ClientVersion string `protobuf:"bytes,4,opt,name=client_version,json=clientVersion" json:"client_version,omitempty"`
+}
+
+func (m *Identity) Reset()                    { *m = Identity{} }
+func (m *Identity) String() string            { return proto.CompactTextString(m) }
+func (*Identity) ProtoMessage()               {}
+func (*Identity) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+
 func init() {
 	proto.RegisterType((*Info)(nil), "hapi.release.Info")
+	proto.RegisterType((*Identity)(nil), "hapi.release.Identity")
 }
 
 func init() { proto.RegisterFile("hapi/release/info.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 212 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xe2, 0x12, 0xcf, 0x48, 0x2c, 0xc8,
-	0xd4, 0x2f, 0x4a, 0xcd, 0x49, 0x4d, 0x2c, 0x4e, 0xd5, 0xcf, 0xcc, 0x4b, 0xcb, 0xd7, 0x2b, 0x28,
-	0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x01, 0x49, 0xe8, 0x41, 0x25, 0xa4, 0xe4, 0xd3, 0xf3, 0xf3, 0xd3,
-	0x73, 0x52, 0xf5, 0xc1, 0x72, 0x49, 0xa5, 0x69, 0xfa, 0x25, 0x99, 0xb9, 0xa9, 0xc5, 0x25, 0x89,
-	0xb9, 0x05, 0x10, 0xe5, 0x52, 0x92, 0x28, 0xe6, 0x14, 0x97, 0x24, 0x96, 0x94, 0x16, 0x43, 0xa4,
-	0x94, 0xde, 0x31, 0x72, 0xb1, 0x78, 0xe6, 0xa5, 0xe5, 0x0b, 0xe9, 0x70, 0xb1, 0x41, 0x24, 0x24,
-	0x18, 0x15, 0x18, 0x35, 0xb8, 0x8d, 0x44, 0xf4, 0x90, 0xed, 0xd0, 0x0b, 0x06, 0xcb, 0x05, 0x41,
-	0xd5, 0x08, 0x39, 0x72, 0xf1, 0xa5, 0x65, 0x16, 0x15, 0x97, 0xc4, 0xa7, 0xa4, 0x16, 0xe4, 0xe4,
-	0x57, 0xa6, 0xa6, 0x48, 0x30, 0x81, 0x75, 0x49, 0xe9, 0x41, 0xdc, 0xa2, 0x07, 0x73, 0x8b, 0x5e,
-	0x08, 0xcc, 0x2d, 0x41, 0xbc, 0x60, 0x1d, 0x2e, 0x50, 0x0d, 0x42, 0xf6, 0x5c, 0xbc, 0x39, 0x89,
-	0xc8, 0x26, 0x30, 0x13, 0x34, 0x81, 0x07, 0xa4, 0x01, 0x6e, 0x80, 0x09, 0x17, 0x7b, 0x4a, 0x6a,
-	0x4e, 0x6a, 0x49, 0x6a, 0x8a, 0x04, 0x0b, 0x41, 0xad, 0x30, 0xa5, 0x4e, 0x9c, 0x51, 0xec, 0x50,
-	0x3f, 0x25, 0xb1, 0x81, 0xd5, 0x19, 0x03, 0x02, 0x00, 0x00, 0xff, 0xff, 0xeb, 0x9d, 0xa1, 0xf8,
-	0x67, 0x01, 0x00, 0x00,
+	// 404 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x84, 0x51, 0x4f, 0x6b, 0xd4, 0x40,
+	0x14, 0x27, 0xdd, 0x6d, 0x36, 0x79, 0xe9, 0x16, 0x19, 0x8a, 0x8d, 0x51, 0x34, 0x14, 0x84, 0x3d,
+	0xc8, 0x04, 0x56, 0x11, 0xf5, 0x22, 0x8a, 0x1e, 0x04, 0x4f, 0xe3, 0x9f, 0x83, 0x97, 0x65, 0xd2,
+	0xbc, 0xb4, 0x43, 0x67, 0x33, 0x61, 0x66, 0x52, 0xc8, 0xd9, 0xcf, 0xe8, 0xf7, 0x91, 0xcc, 0x24,
+	0x6b, 0xea, 0xa5, 0xb7, 0xcc, 0xef, 0xdf, 0xfb, 0xe5, 0x3d, 0x38, 0xbf, 0xe6, 0xad, 0x28, 0x34,
+	0x4a, 0xe4, 0x06, 0x0b, 0xd1, 0xd4, 0x8a, 0xb6, 0x5a, 0x59, 0x45, 0x4e, 0x06, 0x82, 0x8e, 0x44,
+	0xf6, 0xec, 0x4a, 0xa9, 0x2b, 0x89, 0x85, 0xe3, 0xca, 0xae, 0x2e, 0xac, 0xd8, 0xa3, 0xb1, 0x7c,
+	0xdf, 0x7a, 0x79, 0xf6, 0xe8, 0x4e, 0x8e, 0xb1, 0xdc, 0x76, 0xc6, 0x53, 0x17, 0x7f, 0x16, 0xb0,
+	0xfc, 0xd2, 0xd4, 0x8a, 0xbc, 0x80, 0xd0, 0x13, 0x69, 0x90, 0x07, 0x9b, 0x64, 0x7b, 0x46, 0xe7,
+	0x33, 0xe8, 0x37, 0xc7, 0xb1, 0x51, 0x43, 0x3e, 0xc0, 0x69, 0x2d, 0xb4, 0xb1, 0xbb, 0x0a, 0x5b,
+	0xa9, 0x7a, 0xac, 0xd2, 0x23, 0xe7, 0xca, 0xa8, 0xef, 0x42, 0xa7, 0x2e, 0xf4, 0xfb, 0xd4, 0x85,
+	0xad, 0x9d, 0xe3, 0xd3, 0x68, 0x20, 0xef, 0x61, 0x2d, 0xf9, 0x3c, 0x61, 0x71, 0x6f, 0xc2, 0xc9,
+	0x60, 0x38, 0x04, 0xbc, 0x82, 0x55, 0x85, 0x12, 0x2d, 0x56, 0xe9, 0xf2, 0x5e, 0xeb, 0x24, 0x25,
+	0x39, 0x24, 0x15, 0x9a, 0x4b, 0x2d, 0x5a, 0x2b, 0x54, 0x93, 0x1e, 0xe7, 0xc1, 0x26, 0x66, 0x73,
+	0x88, 0xbc, 0x86, 0x50, 0xf2, 0x12, 0xa5, 0x49, 0xc3, 0x7c, 0xb1, 0x49, 0xb6, 0x4f, 0xef, 0x6e,
+	0x62, 0xd8, 0x16, 0xfd, 0xea, 0x04, 0x9f, 0x1b, 0xab, 0x7b, 0x36, 0xaa, 0xc9, 0x16, 0x22, 0x51,
+	0x61, 0x63, 0x85, 0xed, 0xd3, 0x95, 0x2b, 0xf4, 0xf0, 0x3f, 0xe7, 0xc8, 0xb2, 0x83, 0x8e, 0x3c,
+	0x81, 0x58, 0xb5, 0xa8, 0xb9, 0xeb, 0x12, 0xb9, 0x2e, 0xff, 0x80, 0xec, 0x2d, 0x24, 0xb3, 0x41,
+	0xe4, 0x01, 0x2c, 0x6e, 0xb0, 0x77, 0xf7, 0x89, 0xd9, 0xf0, 0x49, 0xce, 0xe0, 0xf8, 0x96, 0xcb,
+	0x0e, 0xdd, 0xf6, 0x63, 0xe6, 0x1f, 0xef, 0x8e, 0xde, 0x04, 0x17, 0xbf, 0x03, 0x88, 0xa6, 0x79,
+	0xe4, 0x31, 0xc4, 0x37, 0x5d, 0x89, 0xbb, 0xce, 0xa0, 0x1e, 0xed, 0xd1, 0x00, 0xfc, 0x30, 0xa8,
+	0xc9, 0x39, 0xac, 0x94, 0xf1, 0x94, 0x4f, 0x09, 0x95, 0x71, 0x44, 0x06, 0xd1, 0xb5, 0x32, 0xb6,
+	0xe1, 0x7b, 0x74, 0xb7, 0x89, 0xd9, 0xe1, 0x4d, 0x9e, 0xc3, 0xe9, 0xa5, 0x14, 0xd8, 0xd8, 0xdd,
+	0x2d, 0x6a, 0x33, 0x94, 0x5f, 0x3a, 0xc5, 0xda, 0xa3, 0x3f, 0x3d, 0xf8, 0x31, 0xfe, 0xb5, 0x1a,
+	0x7f, 0xbe, 0x0c, 0xdd, 0x51, 0x5e, 0xfe, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x19, 0x0a, 0x59, 0xad,
+	0xd4, 0x02, 0x00, 0x00,
 }
@@ -27,6 +27,8 @@ const (
 	Status_SUPERSEDED Status_Code = 3
 	// Status_FAILED indicates that the release was not successfully deployed.
 	Status_FAILED Status_Code = 4
+	// Status_INTERRUPTED indicates that Tiller stopped before the last operation on the release completed.
+	Status_INTERRUPTED Status_Code = 5
 )
 
 var Status_Code_name = map[int32]string{
@@ -35,13 +37,15 @@ var Status_Code_name = map[int32]string{
 	2: "DELETED",
 	3: "SUPERSEDED",
 	4: "FAILED",
+	5: "INTERRUPTED",
 }
 var Status_Code_value = map[string]int32{
-	"UNKNOWN":    0,
-	"DEPLOYED":   1,
-	"DELETED":    2,
-	"SUPERSEDED": 3,
-	"FAILED":     4,
+	"UNKNOWN":     0,
+	"DEPLOYED":    1,
+	"DELETED":     2,
+	"SUPERSEDED":  3,
+	"FAILED":      4,
+	"INTERRUPTED": 5,
 }
 
 func (x Status_Code) String() string {
@@ -79,22 +83,23 @@ func init() {
 func init() { proto.RegisterFile("hapi/release/status.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 261 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0xc1, 0x4e, 0x83, 0x40,
-	0x10, 0x86, 0xdd, 0x16, 0x41, 0xa6, 0x4d, 0x43, 0x36, 0x3d, 0x80, 0xf1, 0x40, 0x7a, 0xe2, 0xe2,
-	0x92, 0xd4, 0x27, 0xa8, 0xee, 0x9a, 0xa8, 0x84, 0x36, 0x60, 0x63, 0xf4, 0x46, 0xcb, 0x58, 0x9b,
-	0x10, 0xb6, 0x61, 0x97, 0x43, 0x9f, 0xd8, 0xd7, 0x30, 0x2c, 0x6d, 0xec, 0x71, 0xe6, 0xfb, 0x66,
-	0xfe, 0x19, 0x08, 0x7e, 0x8a, 0xc3, 0x3e, 0x6e, 0xb0, 0xc2, 0x42, 0x61, 0xac, 0x74, 0xa1, 0x5b,
-	0xc5, 0x0e, 0x8d, 0xd4, 0x92, 0x8e, 0x3b, 0xc4, 0x4e, 0xe8, 0x36, 0xd8, 0x49, 0xb9, 0xab, 0x30,
-	0x36, 0x6c, 0xd3, 0x7e, 0xc7, 0x45, 0x7d, 0xec, 0xc5, 0xd9, 0x2f, 0x01, 0x3b, 0x37, 0x93, 0xf4,
-	0x1e, 0xac, 0xad, 0x2c, 0xd1, 0x27, 0x21, 0x89, 0x26, 0xf3, 0x80, 0x5d, 0xae, 0x60, 0xbd, 0xc3,
-	0x9e, 0x64, 0x89, 0x99, 0xd1, 0x28, 0x03, 0xa7, 0x44, 0x5d, 0xec, 0x2b, 0xe5, 0x0f, 0x42, 0x12,
-	0x8d, 0xe6, 0x53, 0xd6, 0xc7, 0xb0, 0x73, 0x0c, 0x5b, 0xd4, 0xc7, 0xec, 0x2c, 0xd1, 0x3b, 0x70,
-	0x1b, 0x54, 0xb2, 0x6d, 0xb6, 0xa8, 0xfc, 0x61, 0x48, 0x22, 0x37, 0xfb, 0x6f, 0xd0, 0x29, 0x5c,
-	0xd7, 0x52, 0xa3, 0xf2, 0x2d, 0x43, 0xfa, 0x62, 0xf6, 0x0a, 0x56, 0x97, 0x48, 0x47, 0xe0, 0xac,
-	0xd3, 0xb7, 0x74, 0xf9, 0x91, 0x7a, 0x57, 0x74, 0x0c, 0x37, 0x5c, 0xac, 0x92, 0xe5, 0xa7, 0xe0,
-	0x1e, 0xe9, 0x10, 0x17, 0x89, 0x78, 0x17, 0xdc, 0x1b, 0xd0, 0x09, 0x40, 0xbe, 0x5e, 0x89, 0x2c,
-	0x17, 0x5c, 0x70, 0x6f, 0x48, 0x01, 0xec, 0xe7, 0xc5, 0x4b, 0x22, 0xb8, 0x67, 0x3d, 0xba, 0x5f,
-	0xce, 0xe9, 0x99, 0x8d, 0x6d, 0x2e, 0x7c, 0xf8, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xae, 0x07, 0x47,
-	0x1f, 0x41, 0x01, 0x00, 0x00,
+	// 274 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x4c, 0x8f, 0x41, 0x6f, 0xb2, 0x40,
+	0x10, 0x86, 0xbf, 0x55, 0x84, 0x8f, 0xc1, 0x58, 0xb2, 0xf1, 0x00, 0x4d, 0x0f, 0xc4, 0x13, 0x97,
+	0x2e, 0x89, 0xfd, 0x05, 0xb6, 0xbb, 0x4d, 0x4c, 0x09, 0x92, 0x45, 0xd2, 0xb4, 0x3d, 0xa1, 0x6c,
+	0xad, 0x09, 0x61, 0x0d, 0x0b, 0x07, 0xff, 0x79, 0x8f, 0x0d, 0x8b, 0xa6, 0x3d, 0xce, 0x3c, 0xcf,
+	0xcc, 0x3b, 0x03, 0xfe, 0x57, 0x71, 0x3a, 0x46, 0x8d, 0xa8, 0x44, 0xa1, 0x44, 0xa4, 0xda, 0xa2,
+	0xed, 0x14, 0x39, 0x35, 0xb2, 0x95, 0x78, 0xda, 0x23, 0x72, 0x41, 0xb7, 0xfe, 0x41, 0xca, 0x43,
+	0x25, 0x22, 0xcd, 0x76, 0xdd, 0x67, 0x54, 0xd4, 0xe7, 0x41, 0x5c, 0x7c, 0x23, 0x30, 0x33, 0x3d,
+	0x89, 0xef, 0xc1, 0xd8, 0xcb, 0x52, 0x78, 0x28, 0x40, 0xe1, 0x6c, 0xe9, 0x93, 0xbf, 0x2b, 0xc8,
+	0xe0, 0x90, 0x27, 0x59, 0x0a, 0xae, 0x35, 0x4c, 0xc0, 0x2a, 0x45, 0x5b, 0x1c, 0x2b, 0xe5, 0x8d,
+	0x02, 0x14, 0x3a, 0xcb, 0x39, 0x19, 0x62, 0xc8, 0x35, 0x86, 0xac, 0xea, 0x33, 0xbf, 0x4a, 0xf8,
+	0x0e, 0xec, 0x46, 0x28, 0xd9, 0x35, 0x7b, 0xa1, 0xbc, 0x71, 0x80, 0x42, 0x9b, 0xff, 0x36, 0xf0,
+	0x1c, 0x26, 0xb5, 0x6c, 0x85, 0xf2, 0x0c, 0x4d, 0x86, 0x62, 0xf1, 0x01, 0x46, 0x9f, 0x88, 0x1d,
+	0xb0, 0xf2, 0xe4, 0x25, 0xd9, 0xbc, 0x26, 0xee, 0x3f, 0x3c, 0x85, 0xff, 0x94, 0xa5, 0xf1, 0xe6,
+	0x8d, 0x51, 0x17, 0xf5, 0x88, 0xb2, 0x98, 0x6d, 0x19, 0x75, 0x47, 0x78, 0x06, 0x90, 0xe5, 0x29,
+	0xe3, 0x19, 0xa3, 0x8c, 0xba, 0x63, 0x0c, 0x60, 0x3e, 0xaf, 0xd6, 0x31, 0xa3, 0xae, 0x81, 0x6f,
+	0xc0, 0x59, 0x27, 0x5b, 0xc6, 0x79, 0x9e, 0xf6, 0xf2, 0xe4, 0xd1, 0x7e, 0xb7, 0x2e, 0xdf, 0xed,
+	0x4c, 0x7d, 0xf2, 0xc3, 0x4f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x0a, 0x22, 0xb7, 0x8b, 0x52, 0x01,
+	0x00, 0x00,
 }
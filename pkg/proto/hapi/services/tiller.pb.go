@@ -125,6 +125,16 @@ type ListReleasesRequest struct {
 	// SortOrder is the ordering directive used for sorting.
 	SortOrder   ListSort_SortOrder          `protobuf:"varint,5,opt,name=sort_order,json=sortOrder,enum=hapi.services.tiller.ListSort_SortOrder" json:"sort_order,omitempty"`
 	StatusCodes []hapi_release1.Status_Code `protobuf:"varint,6,rep,packed,name=status_codes,json=statusCodes,enum=hapi.release.Status_Code" json:"status_codes,omitempty"`
+	// Namespace restricts the results to releases installed into this namespace.
+	Namespace string `protobuf:"bytes,7,opt,name=namespace" json:"namespace,omitempty"`
+	// ChartName restricts the results to releases of the named chart.
+	ChartName string `protobuf:"bytes,8,opt,name=chart_name,json=chartName" json:"chart_name,omitempty"`
+	// ChartVersion is a SemVer range, e.g. ">= 1.2.0, < 2.0.0", that the chart
+	// version of a release must satisfy.
+	ChartVersion string `protobuf:"bytes,9,opt,name=chart_version,json=chartVersion" json:"chart_version,omitempty"`
+	// Labels restricts the results to releases that carry all of the given
+	// labels. Labels are matched by the storage driver.
+	Labels map[string]string `protobuf:"bytes,10,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *ListReleasesRequest) Reset()                    { *m = ListReleasesRequest{} }
@@ -132,6 +142,13 @@ func (m *ListReleasesRequest) String() string            { return proto.CompactT
 func (*ListReleasesRequest) ProtoMessage()               {}
 func (*ListReleasesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
 
+func (m *ListReleasesRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // ListSort defines sorting fields on a release list.
 type ListSort struct {
 }
@@ -142,6 +159,11 @@ func (*ListSort) ProtoMessage()               {}
 func (*ListSort) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
 
 // ListReleasesResponse is a list of releases.
+//
+// A listing may be split across several messages on the ListReleases stream
+// so that no single message exceeds the gRPC message size limit. Every message
+// in a listing carries the same count, next, and total values; the releases
+// field of each message holds the next batch of results.
 type ListReleasesResponse struct {
 	// Count is the expected total number of releases to be returned.
 	Count int64 `protobuf:"varint,1,opt,name=count" json:"count,omitempty"`
@@ -244,6 +266,11 @@ type UpdateReleaseRequest struct {
 	DryRun bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
 	// DisableHooks causes the server to skip running any hooks for the upgrade.
 	DisableHooks bool `protobuf:"varint,5,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// Description is a human-friendly description of why the upgrade was made.
+	Description string `protobuf:"bytes,6,opt,name=description" json:"description,omitempty"`
+	// Labels are added to the labels of the release, replacing existing
+	// labels with the same key.
+	Labels map[string]string `protobuf:"bytes,7,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *UpdateReleaseRequest) Reset()                    { *m = UpdateReleaseRequest{} }
@@ -265,6 +292,13 @@ func (m *UpdateReleaseRequest) GetValues() *hapi_chart.Config {
 	return nil
 }
 
+func (m *UpdateReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // UpdateReleaseResponse is the response to an update request.
 type UpdateReleaseResponse struct {
 	Release *hapi_release3.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -289,6 +323,11 @@ type RollbackReleaseRequest struct {
 	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
 	// DisableHooks causes the server to skip running any hooks for the rollback
 	DisableHooks bool `protobuf:"varint,3,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// Description is a human-friendly description of why the rollback was made.
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+	// Labels are added to the labels of the release, replacing existing
+	// labels with the same key.
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *RollbackReleaseRequest) Reset()                    { *m = RollbackReleaseRequest{} }
@@ -296,6 +335,13 @@ func (m *RollbackReleaseRequest) String() string            { return proto.Compa
 func (*RollbackReleaseRequest) ProtoMessage()               {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
+func (m *RollbackReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // RollbackReleaseResponse is the response to an update request.
 type RollbackReleaseResponse struct {
 	Release *hapi_release3.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -333,6 +379,10 @@ type InstallReleaseRequest struct {
 	Namespace string `protobuf:"bytes,6,opt,name=namespace" json:"namespace,omitempty"`
 	// ReuseName requests that Tiller re-uses a name, instead of erroring out.
 	ReuseName bool `protobuf:"varint,7,opt,name=reuse_name,json=reuseName" json:"reuse_name,omitempty"`
+	// Description is a human-friendly description of why the install was made.
+	Description string `protobuf:"bytes,8,opt,name=description" json:"description,omitempty"`
+	// Labels are user-supplied key/value pairs attached to the release.
+	Labels map[string]string `protobuf:"bytes,9,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *InstallReleaseRequest) Reset()                    { *m = InstallReleaseRequest{} }
@@ -354,6 +404,13 @@ func (m *InstallReleaseRequest) GetValues() *hapi_chart.Config {
 	return nil
 }
 
+func (m *InstallReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // InstallReleaseResponse is the response from a release installation.
 type InstallReleaseResponse struct {
 	Release *hapi_release3.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
@@ -379,6 +436,11 @@ type UninstallReleaseRequest struct {
 	DisableHooks bool `protobuf:"varint,2,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
 	// Purge removes the release from the store and make its name free for later use.
 	Purge bool `protobuf:"varint,3,opt,name=purge" json:"purge,omitempty"`
+	// Description is a human-friendly description of why the release was deleted.
+	Description string `protobuf:"bytes,4,opt,name=description" json:"description,omitempty"`
+	// Labels are added to the labels of the release, replacing existing
+	// labels with the same key.
+	Labels map[string]string `protobuf:"bytes,5,rep,name=labels" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 }
 
 func (m *UninstallReleaseRequest) Reset()                    { *m = UninstallReleaseRequest{} }
@@ -386,6 +448,13 @@ func (m *UninstallReleaseRequest) String() string            { return proto.Comp
 func (*UninstallReleaseRequest) ProtoMessage()               {}
 func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
+func (m *UninstallReleaseRequest) GetLabels() map[string]string {
+	if m != nil {
+		return m.Labels
+	}
+	return nil
+}
+
 // UninstallReleaseResponse represents a successful response to an uninstall request.
 type UninstallReleaseResponse struct {
 	// Release is the release that was marked deleted.
@@ -807,64 +876,75 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 944 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0x9c, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xae, 0xf3, 0x9f, 0xd3, 0x1f, 0xd2, 0xb3, 0x69, 0xe3, 0x5a, 0x80, 0x22, 0x23, 0xd8, 0xb0,
-	0xb0, 0x29, 0x84, 0x5b, 0x84, 0xd4, 0xcd, 0x46, 0x6d, 0xd9, 0x92, 0x95, 0x26, 0x14, 0x24, 0x2e,
-	0x88, 0xdc, 0x64, 0xb2, 0xf5, 0xae, 0xe3, 0x09, 0x9e, 0x49, 0x45, 0x1f, 0x81, 0x0b, 0x5e, 0x82,
-	0xf7, 0xe0, 0xc9, 0xb8, 0x41, 0x9e, 0xf1, 0xb8, 0x71, 0x62, 0x6f, 0xbd, 0xb9, 0x89, 0x3d, 0x73,
-	0x3e, 0x7f, 0xe7, 0x9b, 0xef, 0xcc, 0x9c, 0x09, 0x58, 0xb7, 0xce, 0xc2, 0x3d, 0xe5, 0x34, 0xb8,
-	0x73, 0x27, 0x94, 0x9f, 0x0a, 0xd7, 0xf3, 0x68, 0xd0, 0x5d, 0x04, 0x4c, 0x30, 0x6c, 0x86, 0xb1,
-	0xae, 0x8e, 0x75, 0x55, 0xcc, 0x3a, 0x96, 0x5f, 0x4c, 0x6e, 0x9d, 0x40, 0xa8, 0x5f, 0x85, 0xb6,
-	0x5a, 0xab, 0xf3, 0xcc, 0x9f, 0xb9, 0x6f, 0xa2, 0x80, 0x4a, 0x11, 0x50, 0x8f, 0x3a, 0x9c, 0xea,
-	0x67, 0xe2, 0x23, 0x1d, 0x73, 0xfd, 0x19, 0x8b, 0x02, 0x27, 0x89, 0x00, 0x17, 0x8e, 0x58, 0xf2,
-	0x04, 0xdf, 0x1d, 0x0d, 0xb8, 0xcb, 0x7c, 0xfd, 0x54, 0x31, 0xfb, 0x9f, 0x02, 0x3c, 0xb9, 0x72,
-	0xb9, 0x20, 0xea, 0x43, 0x4e, 0xe8, 0x1f, 0x4b, 0xca, 0x05, 0x36, 0xa1, 0xec, 0xb9, 0x73, 0x57,
-	0x98, 0x46, 0xdb, 0xe8, 0x14, 0x89, 0x1a, 0xe0, 0x31, 0x54, 0xd8, 0x6c, 0xc6, 0xa9, 0x30, 0x0b,
-	0x6d, 0xa3, 0x53, 0x27, 0xd1, 0x08, 0x7f, 0x80, 0x2a, 0x67, 0x81, 0x18, 0xdf, 0xdc, 0x9b, 0xc5,
-	0xb6, 0xd1, 0x39, 0xe8, 0x7d, 0xde, 0x4d, 0xb3, 0xa2, 0x1b, 0x66, 0x1a, 0xb1, 0x40, 0x74, 0xc3,
-	0x9f, 0x17, 0xf7, 0xa4, 0xc2, 0xe5, 0x33, 0xe4, 0x9d, 0xb9, 0x9e, 0xa0, 0x81, 0x59, 0x52, 0xbc,
-	0x6a, 0x84, 0xe7, 0x00, 0x92, 0x97, 0x05, 0x53, 0x1a, 0x98, 0x65, 0x49, 0xdd, 0xc9, 0x41, 0xfd,
-	0x3a, 0xc4, 0x93, 0x3a, 0xd7, 0xaf, 0xf8, 0x3d, 0xec, 0x29, 0x4b, 0xc6, 0x13, 0x36, 0xa5, 0xdc,
-	0xac, 0xb4, 0x8b, 0x9d, 0x83, 0xde, 0x89, 0xa2, 0xd2, 0x0e, 0x8f, 0x94, 0x69, 0x7d, 0x36, 0xa5,
-	0x64, 0x57, 0xc1, 0xc3, 0x77, 0x6e, 0xff, 0x0e, 0x35, 0x4d, 0x6f, 0xf7, 0xa0, 0xa2, 0xc4, 0xe3,
-	0x2e, 0x54, 0xaf, 0x87, 0xaf, 0x86, 0xaf, 0x7f, 0x1d, 0x36, 0x76, 0xb0, 0x06, 0xa5, 0xe1, 0xd9,
-	0x4f, 0x83, 0x86, 0x81, 0x87, 0xb0, 0x7f, 0x75, 0x36, 0xfa, 0x79, 0x4c, 0x06, 0x57, 0x83, 0xb3,
-	0xd1, 0xe0, 0x65, 0xa3, 0x60, 0x7f, 0x0a, 0xf5, 0x58, 0x15, 0x56, 0xa1, 0x78, 0x36, 0xea, 0xab,
-	0x4f, 0x5e, 0x0e, 0x46, 0xfd, 0x86, 0x61, 0xff, 0x65, 0x40, 0x33, 0x59, 0x04, 0xbe, 0x60, 0x3e,
-	0xa7, 0x61, 0x15, 0x26, 0x6c, 0xe9, 0xc7, 0x55, 0x90, 0x03, 0x44, 0x28, 0xf9, 0xf4, 0x4f, 0x5d,
-	0x03, 0xf9, 0x1e, 0x22, 0x05, 0x13, 0x8e, 0x27, 0xfd, 0x2f, 0x12, 0x35, 0xc0, 0x6f, 0xa1, 0x16,
-	0x2d, 0x8e, 0x9b, 0xa5, 0x76, 0xb1, 0xb3, 0xdb, 0x3b, 0x4a, 0x2e, 0x39, 0xca, 0x48, 0x62, 0x98,
-	0x7d, 0x0e, 0xad, 0x73, 0xaa, 0x95, 0x28, 0x47, 0xf4, 0x9e, 0x08, 0xf3, 0x3a, 0x73, 0x2a, 0xc5,
-	0x84, 0x79, 0x9d, 0x39, 0x45, 0x13, 0xaa, 0xd1, 0x86, 0x92, 0x72, 0xca, 0x44, 0x0f, 0x6d, 0x01,
-	0xe6, 0x26, 0x51, 0xb4, 0xae, 0x34, 0xa6, 0x2f, 0xa0, 0x14, 0x6e, 0x67, 0x49, 0xb3, 0xdb, 0xc3,
-	0xa4, 0xce, 0x4b, 0x7f, 0xc6, 0x88, 0x8c, 0xe3, 0xc7, 0x50, 0x0f, 0xf1, 0x7c, 0xe1, 0x4c, 0xa8,
-	0x5c, 0x6d, 0x9d, 0x3c, 0x4c, 0xd8, 0x17, 0xab, 0x59, 0xfb, 0xcc, 0x17, 0xd4, 0x17, 0xdb, 0xe9,
-	0xbf, 0x82, 0x93, 0x14, 0xa6, 0x68, 0x01, 0xa7, 0x50, 0x8d, 0xa4, 0x49, 0xb6, 0x4c, 0x5f, 0x35,
-	0xca, 0xfe, 0xd7, 0x80, 0xe6, 0xf5, 0x62, 0xea, 0x08, 0xaa, 0x43, 0xef, 0x11, 0xf5, 0x14, 0xca,
-	0xb2, 0x2d, 0x44, 0x5e, 0x1c, 0x2a, 0x6e, 0xd5, 0x3b, 0xfa, 0xe1, 0x2f, 0x51, 0x71, 0x7c, 0x06,
-	0x95, 0x3b, 0xc7, 0x5b, 0x52, 0x2e, 0x8d, 0x88, 0x5d, 0x8b, 0x90, 0xb2, 0xa7, 0x90, 0x08, 0x81,
-	0x2d, 0xa8, 0x4e, 0x83, 0xfb, 0x71, 0xb0, 0xf4, 0xe5, 0x21, 0xab, 0x91, 0xca, 0x34, 0xb8, 0x27,
-	0x4b, 0x1f, 0x3f, 0x83, 0xfd, 0xa9, 0xcb, 0x9d, 0x1b, 0x8f, 0x8e, 0x6f, 0x19, 0x7b, 0xc7, 0xe5,
-	0x39, 0xab, 0x91, 0xbd, 0x68, 0xf2, 0x22, 0x9c, 0xb3, 0x2f, 0xe0, 0x68, 0x4d, 0xfe, 0xb6, 0x4e,
-	0xbc, 0x85, 0x63, 0xc2, 0x3c, 0xef, 0xc6, 0x99, 0xbc, 0xcb, 0x61, 0xc5, 0x8a, 0xea, 0xc2, 0xfb,
-	0x55, 0x17, 0x53, 0x54, 0xff, 0x08, 0xad, 0x8d, 0x5c, 0xdb, 0xea, 0xfe, 0xcf, 0x80, 0xa3, 0x4b,
-	0x9f, 0x0b, 0xc7, 0xf3, 0xd6, 0x74, 0xc7, 0xe5, 0x32, 0x72, 0x97, 0xab, 0xf0, 0x21, 0xe5, 0x2a,
-	0x26, 0x16, 0xae, 0x5d, 0x2a, 0xad, 0xb8, 0x94, 0xa7, 0x84, 0xc9, 0x83, 0x53, 0x59, 0x3b, 0x38,
-	0xf8, 0x09, 0x40, 0x40, 0x97, 0x9c, 0x8e, 0x25, 0x79, 0x55, 0x7e, 0x5f, 0x97, 0x33, 0x43, 0x67,
-	0x4e, 0xed, 0x4b, 0x38, 0x5e, 0x5f, 0xfc, 0xb6, 0x46, 0xde, 0x42, 0xeb, 0xda, 0x77, 0x53, 0x9d,
-	0x4c, 0xdb, 0x01, 0x1b, 0x6b, 0x2b, 0xa4, 0xac, 0xad, 0x09, 0xe5, 0xc5, 0x32, 0x78, 0x43, 0x23,
-	0xaf, 0xd4, 0xc0, 0x7e, 0x05, 0xe6, 0x66, 0xa6, 0x6d, 0x65, 0x3f, 0x81, 0xc3, 0x73, 0x2a, 0x7e,
-	0x51, 0xdd, 0x21, 0x12, 0x6c, 0x0f, 0x00, 0x57, 0x27, 0x1f, 0xb8, 0xa3, 0xa9, 0x24, 0xb7, 0xbe,
-	0x7a, 0x35, 0x5e, 0xa3, 0x7a, 0x7f, 0x57, 0xe1, 0x40, 0x77, 0x4a, 0x75, 0xaf, 0xa1, 0x0b, 0x7b,
-	0xab, 0x57, 0x02, 0x7e, 0x99, 0x7d, 0xed, 0xad, 0xdd, 0xdd, 0xd6, 0xb3, 0x3c, 0x50, 0x25, 0xd5,
-	0xde, 0xf9, 0xc6, 0x40, 0x0e, 0x8d, 0xf5, 0x4e, 0x8d, 0xcf, 0xd3, 0x39, 0x32, 0xae, 0x06, 0xab,
-	0x9b, 0x17, 0xae, 0xd3, 0xe2, 0x9d, 0xb4, 0x33, 0xd9, 0x5e, 0xf1, 0x51, 0x9a, 0x64, 0x47, 0xb7,
-	0x4e, 0x73, 0xe3, 0xe3, 0xbc, 0x6f, 0x61, 0x3f, 0xd1, 0xc8, 0x30, 0xc3, 0xad, 0xb4, 0x66, 0x6d,
-	0x7d, 0x95, 0x0b, 0x1b, 0xe7, 0x9a, 0xc3, 0x41, 0xf2, 0xd0, 0x60, 0x06, 0x41, 0x6a, 0x5f, 0xb1,
-	0xbe, 0xce, 0x07, 0x8e, 0xd3, 0x71, 0x68, 0xac, 0x6f, 0xf7, 0xac, 0x3a, 0x66, 0x1c, 0xc0, 0xac,
-	0x3a, 0x66, 0x9d, 0x22, 0x7b, 0x07, 0x1d, 0x80, 0x87, 0x13, 0x80, 0x4f, 0x33, 0x0b, 0x92, 0x3c,
-	0x38, 0x56, 0xe7, 0x71, 0x60, 0x9c, 0x62, 0x01, 0x1f, 0xad, 0x75, 0x71, 0xcc, 0xb0, 0x26, 0xfd,
-	0x62, 0xb1, 0x9e, 0xe7, 0x44, 0xeb, 0x8c, 0x2f, 0xe0, 0xb7, 0x9a, 0x06, 0xdf, 0x54, 0xe4, 0x1f,
-	0xe5, 0xef, 0xfe, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x0b, 0x37, 0x7c, 0x73, 0xf9, 0x0b, 0x00, 0x00,
+	// 1117 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xac, 0x57, 0xdd, 0x6e, 0xe3, 0x44,
+	0x14, 0x5e, 0xc7, 0x49, 0x9c, 0x9c, 0xb4, 0x25, 0x9d, 0xed, 0x8f, 0x6b, 0x01, 0x8a, 0x8c, 0x60,
+	0x43, 0x77, 0x37, 0x85, 0x20, 0x60, 0x17, 0x21, 0xa4, 0x6e, 0x37, 0x6a, 0xcb, 0x76, 0x53, 0x98,
+	0x50, 0x90, 0xb8, 0x20, 0x72, 0x93, 0xc9, 0xd6, 0xd4, 0xb5, 0x83, 0x67, 0x52, 0x91, 0x47, 0xe0,
+	0x02, 0xae, 0xe1, 0x11, 0x78, 0x35, 0x9e, 0x02, 0xcd, 0x8c, 0xc7, 0x8d, 0x1d, 0x67, 0xeb, 0x46,
+	0xbd, 0x89, 0x3d, 0xe7, 0x7c, 0x73, 0xce, 0xf1, 0xf7, 0xcd, 0x99, 0x99, 0x80, 0x75, 0xe1, 0x8c,
+	0xdd, 0x3d, 0x4a, 0xc2, 0x6b, 0x77, 0x40, 0xe8, 0x1e, 0x73, 0x3d, 0x8f, 0x84, 0xad, 0x71, 0x18,
+	0xb0, 0x00, 0x6d, 0x70, 0x5f, 0x4b, 0xf9, 0x5a, 0xd2, 0x67, 0x6d, 0x89, 0x19, 0x83, 0x0b, 0x27,
+	0x64, 0xf2, 0x57, 0xa2, 0xad, 0xed, 0x59, 0x7b, 0xe0, 0x8f, 0xdc, 0x37, 0x91, 0x43, 0xa6, 0x08,
+	0x89, 0x47, 0x1c, 0x4a, 0xd4, 0x33, 0x31, 0x49, 0xf9, 0x5c, 0x7f, 0x14, 0x44, 0x8e, 0x9d, 0x84,
+	0x83, 0x32, 0x87, 0x4d, 0x68, 0x22, 0xde, 0x35, 0x09, 0xa9, 0x1b, 0xf8, 0xea, 0x29, 0x7d, 0xf6,
+	0x5f, 0x45, 0x78, 0x78, 0xe2, 0x52, 0x86, 0xe5, 0x44, 0x8a, 0xc9, 0x6f, 0x13, 0x42, 0x19, 0xda,
+	0x80, 0x92, 0xe7, 0x5e, 0xb9, 0xcc, 0xd4, 0x1a, 0x5a, 0x53, 0xc7, 0x72, 0x80, 0xb6, 0xa0, 0x1c,
+	0x8c, 0x46, 0x94, 0x30, 0xb3, 0xd0, 0xd0, 0x9a, 0x55, 0x1c, 0x8d, 0xd0, 0x37, 0x60, 0xd0, 0x20,
+	0x64, 0xfd, 0xf3, 0xa9, 0xa9, 0x37, 0xb4, 0xe6, 0x5a, 0xfb, 0xc3, 0x56, 0x16, 0x15, 0x2d, 0x9e,
+	0xa9, 0x17, 0x84, 0xac, 0xc5, 0x7f, 0x5e, 0x4c, 0x71, 0x99, 0x8a, 0x27, 0x8f, 0x3b, 0x72, 0x3d,
+	0x46, 0x42, 0xb3, 0x28, 0xe3, 0xca, 0x11, 0x3a, 0x04, 0x10, 0x71, 0x83, 0x70, 0x48, 0x42, 0xb3,
+	0x24, 0x42, 0x37, 0x73, 0x84, 0x3e, 0xe5, 0x78, 0x5c, 0xa5, 0xea, 0x15, 0x7d, 0x0d, 0x2b, 0x92,
+	0x92, 0xfe, 0x20, 0x18, 0x12, 0x6a, 0x96, 0x1b, 0x7a, 0x73, 0xad, 0xbd, 0x23, 0x43, 0x29, 0x86,
+	0x7b, 0x92, 0xb4, 0x83, 0x60, 0x48, 0x70, 0x4d, 0xc2, 0xf9, 0x3b, 0x45, 0xef, 0x42, 0xd5, 0x77,
+	0xae, 0x08, 0x1d, 0x3b, 0x03, 0x62, 0x1a, 0xa2, 0xc2, 0x1b, 0x03, 0x7a, 0x0f, 0x40, 0x88, 0xd8,
+	0xe7, 0x26, 0xb3, 0x22, 0xdd, 0xc2, 0xd2, 0x75, 0xae, 0x08, 0xfa, 0x00, 0x56, 0xa5, 0x3b, 0x22,
+	0xde, 0xac, 0x0a, 0xc4, 0x8a, 0x30, 0xfe, 0x28, 0x6d, 0xe8, 0x35, 0x94, 0x3d, 0xe7, 0x9c, 0x78,
+	0xd4, 0x84, 0x86, 0xde, 0xac, 0xb5, 0x3f, 0x5f, 0xfc, 0x91, 0x29, 0xa5, 0x5a, 0x27, 0x62, 0x5e,
+	0xc7, 0x67, 0xe1, 0x14, 0x47, 0x41, 0xac, 0xe7, 0x50, 0x9b, 0x31, 0xa3, 0x3a, 0xe8, 0x97, 0x64,
+	0x2a, 0xa4, 0xac, 0x62, 0xfe, 0xca, 0xe5, 0xbd, 0x76, 0xbc, 0x09, 0x89, 0x74, 0x94, 0x83, 0xaf,
+	0x0a, 0xcf, 0x34, 0xfb, 0x17, 0xa8, 0x28, 0x2a, 0xed, 0x36, 0x94, 0xa5, 0x50, 0xa8, 0x06, 0xc6,
+	0x59, 0xf7, 0x55, 0xf7, 0xf4, 0xa7, 0x6e, 0xfd, 0x01, 0xaa, 0x40, 0xb1, 0xbb, 0xff, 0xba, 0x53,
+	0xd7, 0xd0, 0x3a, 0xac, 0x9e, 0xec, 0xf7, 0x7e, 0xe8, 0xe3, 0xce, 0x49, 0x67, 0xbf, 0xd7, 0x79,
+	0x59, 0x2f, 0xd8, 0xef, 0x43, 0x35, 0x56, 0x00, 0x19, 0xa0, 0xef, 0xf7, 0x0e, 0xe4, 0x94, 0x97,
+	0x9d, 0xde, 0x41, 0x5d, 0xb3, 0xff, 0xd0, 0x60, 0x23, 0xf9, 0x19, 0x74, 0x1c, 0xf8, 0x94, 0xf0,
+	0x92, 0x06, 0xc1, 0xc4, 0x8f, 0x57, 0x9c, 0x18, 0x20, 0x04, 0x45, 0x9f, 0xfc, 0xae, 0xd6, 0x9b,
+	0x78, 0xe7, 0x48, 0x16, 0x30, 0xc7, 0x13, 0x6b, 0x4d, 0xc7, 0x72, 0x80, 0x3e, 0x85, 0x4a, 0x24,
+	0x24, 0x35, 0x8b, 0x82, 0xc4, 0xcd, 0xa4, 0xbc, 0x51, 0x46, 0x1c, 0xc3, 0xec, 0x43, 0xd8, 0x3e,
+	0x24, 0xaa, 0x12, 0xa9, 0xbe, 0x5a, 0xff, 0x3c, 0x2f, 0x97, 0x53, 0x8b, 0xf2, 0x72, 0x25, 0x4d,
+	0x30, 0x94, 0x86, 0xbc, 0x9c, 0x12, 0x56, 0x43, 0x9b, 0x81, 0x39, 0x1f, 0x28, 0xfa, 0xae, 0xac,
+	0x48, 0x1f, 0x41, 0x91, 0xb7, 0xae, 0x08, 0x53, 0x6b, 0xa3, 0x64, 0x9d, 0xc7, 0xfe, 0x28, 0xc0,
+	0xc2, 0x9f, 0x5c, 0x78, 0x7a, 0x6a, 0xe1, 0xd9, 0x47, 0xb3, 0x59, 0x0f, 0x02, 0x9f, 0x11, 0x9f,
+	0x2d, 0x57, 0xff, 0x09, 0xec, 0x64, 0x44, 0x8a, 0x3e, 0x60, 0x0f, 0x8c, 0xa8, 0x34, 0x11, 0x6d,
+	0x21, 0xaf, 0x0a, 0x65, 0xff, 0x57, 0x80, 0x8d, 0xb3, 0xf1, 0xd0, 0x61, 0x44, 0xb9, 0xde, 0x52,
+	0xd4, 0x23, 0x28, 0x89, 0x4e, 0x88, 0xb8, 0x58, 0x97, 0xb1, 0xe5, 0x3e, 0x79, 0xc0, 0x7f, 0xb1,
+	0xf4, 0xa3, 0x5d, 0x28, 0x8b, 0x55, 0x4a, 0x05, 0x11, 0x31, 0x6b, 0x11, 0x52, 0xec, 0x9f, 0x38,
+	0x42, 0xa0, 0x6d, 0x30, 0x86, 0xe1, 0xb4, 0x1f, 0x4e, 0x7c, 0xb1, 0xa1, 0x54, 0x70, 0x79, 0x18,
+	0x4e, 0xf1, 0xc4, 0xe7, 0xcd, 0x38, 0x74, 0xa9, 0x73, 0xee, 0x91, 0xfe, 0x45, 0x10, 0x5c, 0x52,
+	0xb1, 0xa7, 0x54, 0xf0, 0x4a, 0x64, 0x3c, 0xe2, 0x36, 0xd4, 0x80, 0xda, 0x90, 0xd0, 0x41, 0xe8,
+	0x8e, 0x19, 0xe7, 0xaa, 0x2c, 0xaa, 0x9d, 0x35, 0xa1, 0x6e, 0xdc, 0xae, 0x86, 0x58, 0x69, 0x5f,
+	0x64, 0xb7, 0x6b, 0x16, 0x09, 0xf7, 0xdd, 0xaf, 0x47, 0xb0, 0x99, 0x4a, 0xb3, 0xac, 0x6c, 0xff,
+	0x14, 0x60, 0x0b, 0x07, 0x9e, 0x77, 0xee, 0x0c, 0x2e, 0x73, 0x08, 0x37, 0xc3, 0x71, 0xe1, 0xed,
+	0x1c, 0xeb, 0xb7, 0x73, 0x5c, 0x9c, 0xe7, 0xf8, 0xbb, 0x98, 0xe3, 0x92, 0xe0, 0xf8, 0x59, 0x36,
+	0xc7, 0xd9, 0x15, 0xdf, 0x37, 0xcb, 0xdf, 0xc2, 0xf6, 0x5c, 0xa2, 0x65, 0x79, 0xfe, 0x57, 0x87,
+	0xcd, 0x63, 0x9f, 0x32, 0xc7, 0xf3, 0x52, 0x34, 0xc7, 0xbd, 0xa0, 0xe5, 0xee, 0x85, 0xc2, 0x5d,
+	0x7a, 0x41, 0x4f, 0xe8, 0xa4, 0x44, 0x2d, 0xce, 0x88, 0x9a, 0xab, 0x3f, 0x12, 0xbb, 0x52, 0x39,
+	0xe3, 0x38, 0x0c, 0xc9, 0x84, 0x12, 0x79, 0x1c, 0x1a, 0x62, 0x7e, 0x55, 0x58, 0xc4, 0x71, 0x98,
+	0x12, 0xbe, 0x32, 0x2f, 0xfc, 0x69, 0x2c, 0x7c, 0x55, 0x08, 0xff, 0x65, 0xb6, 0xf0, 0x99, 0x14,
+	0xde, 0xb7, 0xee, 0xc7, 0xb0, 0x95, 0xce, 0xb3, 0xac, 0xec, 0x7f, 0x17, 0x60, 0xfb, 0xcc, 0x77,
+	0x33, 0x85, 0xcf, 0xea, 0xaf, 0x39, 0x29, 0x0a, 0x19, 0x52, 0x6c, 0x40, 0x69, 0x3c, 0x09, 0xdf,
+	0x90, 0x48, 0x5a, 0x39, 0xc8, 0xd1, 0x5c, 0xdf, 0xa7, 0x9a, 0xeb, 0xf9, 0x82, 0x0d, 0x2c, 0xbb,
+	0xde, 0xfb, 0x66, 0xf9, 0x15, 0x98, 0xf3, 0x99, 0x96, 0xe5, 0xf9, 0x21, 0xac, 0x1f, 0x12, 0x75,
+	0xb1, 0x8a, 0x0a, 0xb6, 0x3b, 0x80, 0x66, 0x8d, 0x37, 0xb1, 0x23, 0x53, 0x32, 0xb6, 0xba, 0x22,
+	0x2b, 0xbc, 0x42, 0xb5, 0xff, 0x34, 0x60, 0x4d, 0x9d, 0xf2, 0x92, 0x2a, 0xe4, 0xc2, 0xca, 0xec,
+	0x75, 0x06, 0x7d, 0x9c, 0xfb, 0xe6, 0x66, 0xed, 0xe6, 0x81, 0xca, 0x52, 0xed, 0x07, 0x9f, 0x68,
+	0x88, 0x42, 0x3d, 0x7d, 0xcb, 0x40, 0x4f, 0xb3, 0x63, 0x2c, 0xb8, 0xd6, 0x58, 0xad, 0xbc, 0x70,
+	0x95, 0x16, 0x5d, 0x0b, 0x3a, 0x93, 0x57, 0x03, 0x74, 0x6b, 0x98, 0xe4, 0x6d, 0xc4, 0xda, 0xcb,
+	0x8d, 0x8f, 0xf3, 0xfe, 0x0a, 0xab, 0x89, 0x73, 0x0d, 0xed, 0xe6, 0x3f, 0x63, 0xad, 0xc7, 0xb9,
+	0xb0, 0x71, 0xae, 0x2b, 0x58, 0x4b, 0x76, 0x39, 0x7a, 0x7c, 0x87, 0x3d, 0xc7, 0x7a, 0x92, 0x0f,
+	0x1c, 0xa7, 0xa3, 0x50, 0x4f, 0x2f, 0xf7, 0x45, 0x3a, 0x2e, 0x68, 0xc0, 0x45, 0x3a, 0x2e, 0xea,
+	0x22, 0xfb, 0x01, 0x72, 0x00, 0x6e, 0x3a, 0x00, 0x3d, 0x5a, 0x28, 0x48, 0xb2, 0x71, 0xac, 0xe6,
+	0xed, 0xc0, 0x38, 0xc5, 0x18, 0xde, 0x49, 0x1d, 0x92, 0xe8, 0xc9, 0x5d, 0x0e, 0x6d, 0xeb, 0x69,
+	0x4e, 0xb4, 0xca, 0xf8, 0x02, 0x7e, 0xae, 0x28, 0xf0, 0x79, 0x59, 0xfc, 0xa1, 0xfd, 0xec, 0xff,
+	0x00, 0x00, 0x00, 0xff, 0xff, 0xfc, 0x54, 0xb2, 0x62, 0xa1, 0x0f, 0x00, 0x00,
 }
@@ -224,6 +224,7 @@ func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 //    "OWNER"          - owner of the configmap, currently "TILLER".
 //    "NAME"           - name of the release.
 //
+// The user-supplied labels of the release are applied as well.
 func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*api.ConfigMap, error) {
 	const owner = "TILLER"
 
@@ -238,6 +239,7 @@ func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*api.Config
 	}
 
 	// apply labels
+	lbs.fromMap(rls.Info.GetLabels())
 	lbs.set("NAME", rls.Name)
 	lbs.set("OWNER", owner)
 	lbs.set("STATUS", rspb.Status_Code_name[int32(rls.Info.Status.Code)])
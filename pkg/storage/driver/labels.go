@@ -20,8 +20,33 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/util/validation"
 )
 
+// reservedLabels are the labels the drivers set on every release record.
+var reservedLabels = []string{"NAME", "OWNER", "STATUS", "VERSION", "CREATED_AT", "MODIFIED_AT"}
+
+// ValidateLabels checks that user-supplied release labels can be stored
+// alongside the labels the drivers set on each release record.
+func ValidateLabels(lbs map[string]string) error {
+	for k, v := range lbs {
+		for _, r := range reservedLabels {
+			if k == r {
+				return fmt.Errorf("label %q is reserved", k)
+			}
+		}
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", k, strings.Join(errs, "; "))
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			return fmt.Errorf("invalid label value %q: %s", v, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
 // labels is a map of key value pairs to be included as metadata in a configmap object.
 type labels map[string]string
 
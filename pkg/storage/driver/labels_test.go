@@ -47,3 +47,23 @@ func TestLabelsMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateLabels(t *testing.T) {
+	var tests = []struct {
+		desc   string
+		lbs    map[string]string
+		expect bool
+	}{
+		{"no labels", nil, true},
+		{"valid labels", map[string]string{"team": "payments", "example.com/env": "prod"}, true},
+		{"reserved label", map[string]string{"STATUS": "DEPLOYED"}, false},
+		{"invalid key", map[string]string{"team name": "payments"}, false},
+		{"invalid value", map[string]string{"team": "pay ments"}, false},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateLabels(tt.lbs); (err == nil) != tt.expect {
+			t.Errorf("%s: expected valid = %t, got error %v", tt.desc, tt.expect, err)
+		}
+	}
+}
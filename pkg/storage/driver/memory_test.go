@@ -91,6 +91,11 @@ func TestMemoryQuery(t *testing.T) {
 			2,
 			map[string]string{"STATUS": "DEPLOYED"},
 		},
+		{
+			"should be 1 query result",
+			1,
+			map[string]string{"team": "payments"},
+		},
 	}
 
 	ts := tsFixtureMemory(t)
@@ -124,6 +124,7 @@ func newRecord(key string, rls *rspb.Release) *record {
 	var lbs labels
 
 	lbs.init()
+	lbs.fromMap(rls.Info.GetLabels())
 	lbs.set("NAME", rls.Name)
 	lbs.set("OWNER", "TILLER")
 	lbs.set("STATUS", rspb.Status_Code_name[int32(rls.Info.Status.Code)])
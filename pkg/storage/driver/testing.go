@@ -51,6 +51,7 @@ func tsFixtureMemory(t *testing.T) *Memory {
 		releaseStub("rls-b", 3, rspb.Status_SUPERSEDED),
 		releaseStub("rls-b", 2, rspb.Status_SUPERSEDED),
 	}
+	hs[4].Info.Labels = map[string]string{"team": "payments"}
 
 	mem := NewMemory()
 	for _, tt := range hs {
@@ -16,7 +16,11 @@ limitations under the License.
 
 package storage // import "k8s.io/helm/pkg/storage"
 
-import rspb "k8s.io/helm/pkg/proto/hapi/release"
+import (
+	"github.com/Masterminds/semver"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
 
 // FilterFunc returns true if the release object satisfies
 // the predicate of the underlying func.
@@ -65,3 +69,36 @@ func StatusFilter(status rspb.Status_Code) FilterFunc {
 		return rls.GetInfo().GetStatus().Code == status
 	})
 }
+
+// NamespaceFilter filters a set of releases by the namespace they were
+// installed into.
+func NamespaceFilter(namespace string) FilterFunc {
+	return FilterFunc(func(rls *rspb.Release) bool {
+		return rls.Namespace == namespace
+	})
+}
+
+// ChartNameFilter filters a set of releases by the name of their chart.
+func ChartNameFilter(name string) FilterFunc {
+	return FilterFunc(func(rls *rspb.Release) bool {
+		md := rls.GetChart().GetMetadata()
+		return md != nil && md.Name == name
+	})
+}
+
+// ChartVersionFilter filters a set of releases by checking their chart
+// version against a SemVer range. Releases whose chart version is not valid
+// SemVer never match.
+func ChartVersionFilter(constraint *semver.Constraints) FilterFunc {
+	return FilterFunc(func(rls *rspb.Release) bool {
+		md := rls.GetChart().GetMetadata()
+		if md == nil {
+			return false
+		}
+		v, err := semver.NewVersion(md.Version)
+		if err != nil {
+			return false
+		}
+		return constraint.Check(v)
+	})
+}
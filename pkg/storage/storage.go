@@ -106,6 +106,36 @@ func (s *Storage) ListFilterAny(filters ...FilterFunc) ([]*rspb.Release, error)
 	})
 }
 
+// QueryFilterAll returns the set of releases that carry all of the provided
+// labels and satisfy the predicate (filter0 && filter1 && ... && filterN).
+// The labels are matched by the underlying driver, so only the releases it
+// returns are checked against the filters.
+func (s *Storage) QueryFilterAll(labels map[string]string, filters ...FilterFunc) ([]*rspb.Release, error) {
+	log.Println("Querying releases with labels and filter")
+
+	query := map[string]string{}
+	for k, v := range labels {
+		query[k] = v
+	}
+	query["OWNER"] = "TILLER"
+
+	ls, err := s.Driver.Query(query)
+	switch {
+	case err == driver.ErrReleaseNotFound:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	var results []*rspb.Release
+	for _, rls := range ls {
+		if All(filters...).Check(rls) {
+			results = append(results, rls)
+		}
+	}
+	return results, nil
+}
+
 // Deployed returns the deployed release with the provided release name, or
 // returns ErrReleaseNotFound if not found.
 func (s *Storage) Deployed(name string) (*rspb.Release, error) {
@@ -21,6 +21,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/Masterminds/semver"
+
+	cpb "k8s.io/helm/pkg/proto/hapi/chart"
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/storage/driver"
 )
@@ -186,12 +189,67 @@ func TestStorageDeployed(t *testing.T) {
 	}
 }
 
+func TestStorageQueryFilterAll(t *testing.T) {
+	storage := Init(driver.NewMemory())
+
+	// setup storage with test releases
+	setup := func() {
+		rls0 := ReleaseTestData{Name: "happy-catdog", Namespace: "payments", ChartName: "nginx", ChartVersion: "1.2.0", Status: rspb.Status_DEPLOYED}.ToRelease()
+		rls1 := ReleaseTestData{Name: "livid-human", Namespace: "payments", ChartName: "nginx", ChartVersion: "2.0.1", Status: rspb.Status_DEPLOYED}.ToRelease()
+		rls2 := ReleaseTestData{Name: "relaxed-cat", Namespace: "payments", ChartName: "redis", ChartVersion: "1.0.0", Status: rspb.Status_DEPLOYED}.ToRelease()
+		rls3 := ReleaseTestData{Name: "hungry-hippo", Namespace: "search", ChartName: "nginx", ChartVersion: "1.4.0", Status: rspb.Status_DEPLOYED}.ToRelease()
+		rls4 := ReleaseTestData{Name: "angry-beaver", Namespace: "search", ChartName: "nginx", ChartVersion: "1.4.0", Status: rspb.Status_DELETED}.ToRelease()
+
+		assertErrNil(t.Fatal, storage.Create(rls0), "Storing release 'rls0'")
+		assertErrNil(t.Fatal, storage.Create(rls1), "Storing release 'rls1'")
+		assertErrNil(t.Fatal, storage.Create(rls2), "Storing release 'rls2'")
+		assertErrNil(t.Fatal, storage.Create(rls3), "Storing release 'rls3'")
+		assertErrNil(t.Fatal, storage.Create(rls4), "Storing release 'rls4'")
+	}
+
+	constraint := func(c string) *semver.Constraints {
+		sc, err := semver.NewConstraint(c)
+		assertErrNil(t.Fatal, err, "NewConstraint")
+		return sc
+	}
+
+	var queryTests = []struct {
+		Description string
+		NumExpected int
+		Labels      map[string]string
+		Filters     []FilterFunc
+	}{
+		{"Everything", 5, nil, nil},
+		{"Deployed", 4, map[string]string{"STATUS": "DEPLOYED"}, nil},
+		{"Namespace", 3, nil, []FilterFunc{NamespaceFilter("payments")}},
+		{"ChartName", 4, nil, []FilterFunc{ChartNameFilter("nginx")}},
+		{"ChartVersion", 3, nil, []FilterFunc{ChartNameFilter("nginx"), ChartVersionFilter(constraint("^1.0.0"))}},
+		{"Combined", 1, map[string]string{"STATUS": "DEPLOYED"}, []FilterFunc{NamespaceFilter("search"), ChartNameFilter("nginx")}},
+		{"NoMatch", 0, map[string]string{"NAME": "sad-panda"}, nil},
+	}
+
+	setup()
+
+	for _, tt := range queryTests {
+		list, err := storage.QueryFilterAll(tt.Labels, tt.Filters...)
+		assertErrNil(t.Fatal, err, tt.Description)
+		if len(list) != tt.NumExpected {
+			t.Errorf("QueryFilterAll(%s): expected %d, actual %d",
+				tt.Description,
+				tt.NumExpected,
+				len(list))
+		}
+	}
+}
+
 type ReleaseTestData struct {
-	Name      string
-	Version   int32
-	Manifest  string
-	Namespace string
-	Status    rspb.Status_Code
+	Name         string
+	Version      int32
+	Manifest     string
+	Namespace    string
+	Status       rspb.Status_Code
+	ChartName    string
+	ChartVersion string
 }
 
 func (test ReleaseTestData) ToRelease() *rspb.Release {
@@ -201,6 +259,9 @@ func (test ReleaseTestData) ToRelease() *rspb.Release {
 		Manifest:  test.Manifest,
 		Namespace: test.Namespace,
 		Info:      &rspb.Info{Status: &rspb.Status{Code: test.Status}},
+		Chart: &cpb.Chart{
+			Metadata: &cpb.Metadata{Name: test.ChartName, Version: test.ChartVersion},
+		},
 	}
 }
 
@@ -0,0 +1,34 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package strvals parses the values that are set on the command line, as in
+'helm install --set'.
+
+A line of values is a comma-separated list of assignments:
+
+	name=value,image.tag=1.2,servers[0].port=80,hosts={a.example.com,b.example.com}
+
+Dots in a name create nested tables, and an index in brackets sets an element
+of a list, extending the list as needed. A value in braces is a list. A
+backslash escapes the next character, so 'a\.b=x\,y' sets the key "a.b" to
+"x,y".
+
+Parse infers the types of values: true and false are booleans, null is null,
+and integers are integers. All other values are strings. ParseString keeps
+every value a string.
+*/
+package strvals // import "k8s.io/helm/pkg/strvals"
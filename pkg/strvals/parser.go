@@ -0,0 +1,265 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strvals
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// maxIndex is the largest list index that may be set, so that a typo cannot
+// allocate a huge list.
+const maxIndex = 65535
+
+// Parse parses a line of values into a map.
+func Parse(s string) (map[string]interface{}, error) {
+	vals := map[string]interface{}{}
+	return vals, ParseInto(s, vals)
+}
+
+// ParseString parses a line of values into a map, keeping every value a string.
+func ParseString(s string) (map[string]interface{}, error) {
+	vals := map[string]interface{}{}
+	return vals, ParseIntoString(s, vals)
+}
+
+// ParseInto parses a line of values into dest, replacing the values that are
+// already set at the same keys.
+func ParseInto(s string, dest map[string]interface{}) error {
+	return parse(s, dest, func(v string) (interface{}, error) { return typed(v), nil }, true)
+}
+
+// ParseIntoString parses a line of values into dest like ParseInto, keeping
+// every value a string.
+func ParseIntoString(s string, dest map[string]interface{}) error {
+	return parse(s, dest, func(v string) (interface{}, error) { return v, nil }, true)
+}
+
+// ParseIntoFile parses a line of values into dest like ParseIntoString, but
+// the values are the paths of files, and the keys are set to the contents of
+// those files.
+func ParseIntoFile(s string, dest map[string]interface{}) error {
+	return parse(s, dest, func(path string) (interface{}, error) {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}, false)
+}
+
+// parse parses the assignments in s into dest, converting each value with
+// value. If bare is true, a key without a value is set to true.
+func parse(s string, dest map[string]interface{}, value func(string) (interface{}, error), bare bool) error {
+	if s == "" {
+		return nil
+	}
+	for _, a := range split(s, ',', true) {
+		var path []step
+		var val interface{}
+
+		i := index(a, '=')
+		if i < 0 {
+			if !bare {
+				return fmt.Errorf("key %q has no value", unescape(a))
+			}
+			var err error
+			if path, err = parseKey(a); err != nil {
+				return err
+			}
+			val = true
+		} else {
+			var err error
+			if path, err = parseKey(a[:i]); err != nil {
+				return err
+			}
+			if val, err = parseValue(a[i+1:], value); err != nil {
+				return fmt.Errorf("key %q: %s", unescape(a[:i]), err)
+			}
+		}
+
+		if _, err := set(dest, path, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseValue parses a value, which is a list if it is in braces.
+func parseValue(v string, value func(string) (interface{}, error)) (interface{}, error) {
+	if len(v) < 2 || v[0] != '{' || v[len(v)-1] != '}' || strings.HasSuffix(v, `\}`) {
+		return value(unescape(v))
+	}
+	list := []interface{}{}
+	inner := v[1 : len(v)-1]
+	if inner == "" {
+		return list, nil
+	}
+	for _, item := range split(inner, ',', false) {
+		val, err := value(unescape(item))
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, val)
+	}
+	return list, nil
+}
+
+// step is one element of the path of a key: the name of a key in a table,
+// or, if index is not negative, an index into a list.
+type step struct {
+	key   string
+	index int
+}
+
+// parseKey parses a key such as "a.b[0].c" into its path.
+func parseKey(k string) ([]step, error) {
+	var path []step
+	for _, seg := range split(k, '.', false) {
+		name := seg
+		var indexes string
+		if i := index(seg, '['); i >= 0 {
+			name, indexes = seg[:i], seg[i:]
+		}
+		if name == "" {
+			return nil, fmt.Errorf("key %q has an empty name", unescape(k))
+		}
+		path = append(path, step{key: unescape(name), index: -1})
+
+		for indexes != "" {
+			end := strings.IndexByte(indexes, ']')
+			if indexes[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("key %q has a malformed index", unescape(k))
+			}
+			n, err := strconv.Atoi(indexes[1:end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("key %q has a malformed index %q", unescape(k), indexes[1:end])
+			}
+			if n > maxIndex {
+				return nil, fmt.Errorf("key %q has an index greater than %d", unescape(k), maxIndex)
+			}
+			path = append(path, step{index: n})
+			indexes = indexes[end+1:]
+		}
+	}
+	return path, nil
+}
+
+// set sets the value at path within cur, creating tables and lists as
+// needed, and returns the updated cur. A table or list that is in the way of
+// path is replaced.
+func set(cur interface{}, path []step, val interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return val, nil
+	}
+	s := path[0]
+	if s.index < 0 {
+		t, ok := cur.(map[string]interface{})
+		if !ok {
+			t = map[string]interface{}{}
+		}
+		v, err := set(t[s.key], path[1:], val)
+		t[s.key] = v
+		return t, err
+	}
+
+	l, _ := cur.([]interface{})
+	if s.index >= len(l) {
+		nl := make([]interface{}, s.index+1)
+		copy(nl, l)
+		l = nl
+	}
+	v, err := set(l[s.index], path[1:], val)
+	l[s.index] = v
+	return l, err
+}
+
+// typed returns the boolean, null or integer that v spells, or else v.
+func typed(v string) interface{} {
+	switch v {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	// Only plainly written integers are converted, so that "0123" stays a string.
+	if i, err := strconv.ParseInt(v, 10, 64); err == nil && strconv.FormatInt(i, 10) == v {
+		return i
+	}
+	return v
+}
+
+// split splits s at each unescaped sep, keeping escapes. If lists is true,
+// a sep inside braces that directly follow an unescaped '=' does not split.
+func split(s string, sep rune, lists bool) []string {
+	var parts []string
+	start, depth := 0, 0
+	escaped := false
+	var prev rune
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+			prev = 0
+			continue
+		case r == '\\':
+			escaped = true
+		case lists && r == '{' && prev == '=':
+			depth++
+		case lists && r == '}' && depth > 0:
+			depth--
+		case r == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+		prev = r
+	}
+	return append(parts, s[start:])
+}
+
+// index returns the index of the first unescaped c in s, or -1.
+func index(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case c:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape removes the backslashes that escape characters in s.
+func unescape(s string) string {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s
+	}
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strvals
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		str    string
+		expect map[string]interface{}
+		err    bool
+	}{
+		{"name=value", map[string]interface{}{"name": "value"}, false},
+		{"name=value,age=12", map[string]interface{}{"name": "value", "age": int64(12)}, false},
+		{"good", map[string]interface{}{"good": true}, false},
+		{"name=", map[string]interface{}{"name": ""}, false},
+		{"on=true,off=false,gone=null", map[string]interface{}{"on": true, "off": false, "gone": nil}, false},
+		{"zip=0123,version=1.10,neg=-5", map[string]interface{}{"zip": "0123", "version": "1.10", "neg": int64(-5)}, false},
+		{"image.tag=1.2,image.name=alpine", map[string]interface{}{
+			"image": map[string]interface{}{"tag": "1.2", "name": "alpine"},
+		}, false},
+		{"list[0]=a,list[2]=c", map[string]interface{}{"list": []interface{}{"a", nil, "c"}}, false},
+		{"servers[0].port=80,servers[0].host=a,servers[1].port=443", map[string]interface{}{
+			"servers": []interface{}{
+				map[string]interface{}{"port": int64(80), "host": "a"},
+				map[string]interface{}{"port": int64(443)},
+			},
+		}, false},
+		{"matrix[0][1]=x", map[string]interface{}{"matrix": []interface{}{[]interface{}{nil, "x"}}}, false},
+		{"hosts={a.example.com,b.example.com},ports={80,443},none={}", map[string]interface{}{
+			"hosts": []interface{}{"a.example.com", "b.example.com"},
+			"ports": []interface{}{int64(80), int64(443)},
+			"none":  []interface{}{},
+		}, false},
+		{`name=a\,b,path=C:\\dir`, map[string]interface{}{"name": "a,b", "path": `C:\dir`}, false},
+		{`a\.b=x,c=\{d\}`, map[string]interface{}{"a.b": "x", "c": "{d}"}, false},
+		{"expr=a=b", map[string]interface{}{"expr": "a=b"}, false},
+		{"name=x,name=y", map[string]interface{}{"name": "y"}, false},
+		{"a.b=x,a=y", map[string]interface{}{"a": "y"}, false},
+		{"a=y,a.b=x", map[string]interface{}{"a": map[string]interface{}{"b": "x"}}, false},
+
+		{"=value", nil, true},
+		{"a..b=x", nil, true},
+		{"list[x]=a", nil, true},
+		{"list[-1]=a", nil, true},
+		{"list[0=a", nil, true},
+		{"list[100000]=a", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.str)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%s: Expected an error, got %v", tt.str, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %s", tt.str, err)
+			continue
+		}
+		assertEqual(t, tt.str, tt.expect, got)
+	}
+}
+
+func TestParseString(t *testing.T) {
+	got, err := ParseString("port=80,on=true,list={1,2},a.b=null")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"port": "80",
+		"on":   "true",
+		"list": []interface{}{"1", "2"},
+		"a":    map[string]interface{}{"b": "null"},
+	}
+	assertEqual(t, "ParseString", expect, got)
+}
+
+func TestParseInto(t *testing.T) {
+	dest := map[string]interface{}{
+		"image": map[string]interface{}{"name": "alpine", "tag": "3.4"},
+		"ports": []interface{}{int64(80), int64(443)},
+	}
+	if err := ParseInto("image.tag=3.5,ports[1]=8443", dest); err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseIntoString("count=3", dest); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"image": map[string]interface{}{"name": "alpine", "tag": "3.5"},
+		"ports": []interface{}{int64(80), int64(8443)},
+		"count": "3",
+	}
+	assertEqual(t, "ParseInto", expect, dest)
+}
+
+func TestParseIntoFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-strvals-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "script.sh")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\necho 12\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := map[string]interface{}{}
+	if err := ParseIntoFile("config.script="+path, dest); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"config": map[string]interface{}{"script": "#!/bin/sh\necho 12\n"},
+	}
+	assertEqual(t, "ParseIntoFile", expect, dest)
+
+	if err := ParseIntoFile("script", dest); err == nil {
+		t.Error("Expected an error for a key without a file")
+	}
+	if err := ParseIntoFile("script="+filepath.Join(dir, "missing"), dest); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func assertEqual(t *testing.T, name string, expect, got map[string]interface{}) {
+	e, err := yaml.Marshal(expect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := yaml.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(e) != string(g) {
+		t.Errorf("%s: Expected:\n%s\nGot:\n%s", name, e, g)
+	}
+}
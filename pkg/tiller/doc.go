@@ -0,0 +1,25 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*
+Package tiller implements Tiller's release management.
+
+ReleaseServer installs, upgrades, rolls back and deletes releases, running
+their hooks, and records them in the storage of the environment it is created
+with. It implements the gRPC ReleaseService, and may also be embedded directly
+in other programs.
+*/
+package tiller // import "k8s.io/helm/pkg/tiller"
@@ -24,6 +24,7 @@ package environment
 
 import (
 	"io"
+	"os"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/engine"
@@ -35,12 +36,19 @@ import (
 	"k8s.io/kubernetes/pkg/client/unversioned/testclient"
 )
 
-// TillerNamespace is the namespace tiller is running in.
-const TillerNamespace = "kube-system"
+// DefaultTillerNamespace is the namespace tiller runs in if TillerNamespaceEnvVar is not set.
+const DefaultTillerNamespace = "kube-system"
+
+// TillerNamespaceEnvVar is the environment variable holding the namespace tiller is running in.
+const TillerNamespaceEnvVar = "TILLER_NAMESPACE"
 
 // GoTplEngine is the name of the Go template engine, as registered in the EngineYard.
 const GoTplEngine = "gotpl"
 
+// RawEngine is the name of the engine that renders templates unmodified, as
+// registered in the EngineYard.
+const RawEngine = "raw"
+
 // DefaultEngine points to the engine that the EngineYard should treat as the
 // default. A chart that does not specify an engine may be run through the
 // default engine.
@@ -135,6 +143,13 @@ type KubeClient interface {
 	// by "\n---\n").
 	Update(namespace string, originalReader, modifiedReader io.Reader) error
 
+	// Namespaces returns the namespace of each namespaced resource that
+	// Create would create, including the items of lists.
+	//
+	// Resources that do not set a namespace are in namespace. An error is
+	// returned if reader contains a document that is not a resource.
+	Namespaces(namespace string, reader io.Reader) ([]string, error)
+
 	// APIClient gets a raw API client for Kubernetes.
 	APIClient() (unversioned.Interface, error)
 }
@@ -185,6 +200,13 @@ func (p *PrintingKubeClient) Update(ns string, currentReader, modifiedReader io.
 	return err
 }
 
+// Namespaces implements KubeClient Namespaces.
+//
+// The printing client does not decode resources, so it returns no namespaces.
+func (p *PrintingKubeClient) Namespaces(ns string, r io.Reader) ([]string, error) {
+	return nil, nil
+}
+
 // Environment provides the context for executing a client request.
 //
 // All services in a context are concurrency safe.
@@ -195,20 +217,44 @@ type Environment struct {
 	Releases *storage.Storage
 	// KubeClient is a Kubernetes API client.
 	KubeClient KubeClient
+	// Namespace is the namespace tiller is running in. Releases are stored here.
+	Namespace string
+	// AllowedNamespaces restricts the namespaces tiller may manage resources
+	// in. If empty, every namespace is allowed.
+	AllowedNamespaces []string
+}
+
+// NamespaceAllowed reports whether tiller may manage resources in namespace.
+func (e *Environment) NamespaceAllowed(namespace string) bool {
+	if len(e.AllowedNamespaces) == 0 {
+		return true
+	}
+	for _, ns := range e.AllowedNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
 }
 
 // New returns an environment initialized with the defaults.
 func New() *Environment {
 	e := engine.New()
 	var ey EngineYard = map[string]Engine{
-		// Currently, the only template engine we support is the GoTpl one. But
-		// we can easily add some here.
+		// More engines, such as engine.Exec engines, can be added here.
 		GoTplEngine: e,
+		RawEngine:   engine.Raw{},
+	}
+
+	ns := os.Getenv(TillerNamespaceEnvVar)
+	if ns == "" {
+		ns = DefaultTillerNamespace
 	}
 
 	return &Environment{
 		EngineYard: ey,
 		Releases:   storage.Init(driver.NewMemory()),
 		KubeClient: kube.New(nil),
+		Namespace:  ns,
 	}
 }
@@ -57,6 +57,9 @@ func (k *mockKubeClient) Update(ns string, currentReader, modifiedReader io.Read
 func (k *mockKubeClient) WatchUntilReady(ns string, r io.Reader) error {
 	return nil
 }
+func (k *mockKubeClient) Namespaces(ns string, r io.Reader) ([]string, error) {
+	return nil, nil
+}
 
 var _ Engine = &mockEngine{}
 var _ KubeClient = &mockKubeClient{}
@@ -97,3 +100,18 @@ func TestKubeClient(t *testing.T) {
 		t.Errorf("Kubeclient failed: %s", err)
 	}
 }
+
+func TestNamespaceAllowed(t *testing.T) {
+	env := New()
+	if !env.NamespaceAllowed("anything") {
+		t.Error("expected every namespace to be allowed without an allow-list")
+	}
+
+	env.AllowedNamespaces = []string{"team-a", "team-b"}
+	if !env.NamespaceAllowed("team-b") {
+		t.Error("expected team-b to be allowed")
+	}
+	if env.NamespaceAllowed("kube-system") {
+		t.Error("expected kube-system to be rejected")
+	}
+}
@@ -14,15 +14,17 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package tiller
 
 import (
 	"fmt"
 	"log"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/ghodss/yaml"
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
@@ -56,25 +58,11 @@ type simpleHead struct {
 	Kind     string `json:"kind,omitempty"`
 	Metadata *struct {
 		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
 		Annotations map[string]string `json:"annotations"`
 	} `json:"metadata,omitempty"`
 }
 
-type versionSet map[string]struct{}
-
-func newVersionSet(apiVersions ...string) versionSet {
-	vs := versionSet{}
-	for _, v := range apiVersions {
-		vs[v] = struct{}{}
-	}
-	return vs
-}
-
-func (v versionSet) Has(apiVersion string) bool {
-	_, ok := v[apiVersion]
-	return ok
-}
-
 // manifest represents a manifest file, which has a name and some content.
 type manifest struct {
 	name    string
@@ -102,12 +90,21 @@ type manifest struct {
 // hook buckets. (Note: label keys are not unique within the labels section).
 //
 // Files that do not parse into the expected format are simply placed into a map and
-// returned.
-func sortManifests(files map[string]string, apis versionSet, sort SortOrder) ([]*release.Hook, []manifest, error) {
+// returned. Files are visited in name order, so the results are deterministic.
+//
+// If apis is nil, the apiVersion of the files is not checked.
+func sortManifests(files map[string]string, apis chartutil.VersionSet, ordering SortOrder) ([]*release.Hook, []manifest, error) {
 	hs := []*release.Hook{}
 	generic := []manifest{}
 
-	for n, c := range files {
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		c := files[n]
 		// Skip partials. We could return these as a separate map, but there doesn't
 		// seem to be any need for that at this time.
 		if strings.HasPrefix(path.Base(n), "_") {
@@ -127,7 +124,7 @@ func sortManifests(files map[string]string, apis versionSet, sort SortOrder) ([]
 			return hs, generic, e
 		}
 
-		if sh.Version != "" && !apis.Has(sh.Version) {
+		if apis != nil && sh.Version != "" && !apis.Has(sh.Version) {
 			return hs, generic, fmt.Errorf("apiVersion %q in %s is not available", sh.Version, n)
 		}
 
@@ -0,0 +1,106 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// inflightReleases tracks the releases that are being operated on. The zero
+// value is ready to use.
+//
+// Each release is kept with a copy taken when its operation started, so that
+// it can be recorded as interrupted without touching the release the
+// operation is still modifying.
+type inflightReleases struct {
+	mu       sync.Mutex
+	releases map[*release.Release]*release.Release
+	// stopped is set once the releases have been interrupted. No operation
+	// may record a release after that.
+	stopped bool
+}
+
+func (f *inflightReleases) add(r *release.Release) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.releases == nil {
+		f.releases = map[*release.Release]*release.Release{}
+	}
+	f.releases[r] = proto.Clone(r).(*release.Release)
+}
+
+func (f *inflightReleases) remove(r *release.Release) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.releases, r)
+}
+
+// finish removes r, and returns whether its operation may still record it.
+// It returns false once the releases have been interrupted.
+func (f *inflightReleases) finish(r *release.Release) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.releases, r)
+	return !f.stopped
+}
+
+func (f *inflightReleases) list() []*release.Release {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rels := make([]*release.Release, 0, len(f.releases))
+	for r := range f.releases {
+		rels = append(rels, r)
+	}
+	return rels
+}
+
+// errInterrupted is returned by operations whose release was recorded as
+// interrupted before they completed.
+var errInterrupted = errors.New("the release was interrupted by a shutdown of tiller")
+
+// InterruptReleases records the releases that are still being operated on
+// with an INTERRUPTED status, rather than leaving them in whatever state the
+// operation had reached. It is called when the server is stopped before those
+// operations complete.
+//
+// The operations are not stopped, but they no longer record their releases,
+// so that the INTERRUPTED status is kept.
+func (s *ReleaseServer) InterruptReleases() {
+	f := &s.inflight
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+	for orig, r := range f.releases {
+		log.Printf("warning: Release %q (v%d) was interrupted", r.Name, r.Version)
+		r.Info.Status.Code = release.Status_INTERRUPTED
+		err := s.env.Releases.Create(r)
+		if err == driver.ErrReleaseExists {
+			err = s.env.Releases.Update(r)
+		}
+		if err != nil {
+			log.Printf("warning: Failed to record interrupted release %q: %s", r.Name, err)
+		}
+		delete(f.releases, orig)
+	}
+}
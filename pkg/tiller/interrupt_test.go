@@ -0,0 +1,114 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func TestInterruptReleasesUpdatesStored(t *testing.T) {
+	rs := rsFixture()
+	rel := namedReleaseStub("deleting", release.Status_DELETED)
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatal(err)
+	}
+	rs.inflight.add(rel)
+
+	rs.InterruptReleases()
+
+	got, err := rs.env.Releases.Get("deleting", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Info.Status.Code != release.Status_INTERRUPTED {
+		t.Errorf("Expected release to be INTERRUPTED, got %s", got.Info.Status.Code)
+	}
+	if rels := rs.inflight.list(); len(rels) != 0 {
+		t.Errorf("Expected no in-flight releases, got %d", len(rels))
+	}
+}
+
+func TestInstallReleaseNotInflight(t *testing.T) {
+	rs := rsFixture()
+	req := &services.InstallReleaseRequest{Chart: chartStub(), Namespace: "spaced"}
+	if _, err := rs.InstallRelease(helm.NewContext(), req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if rels := rs.inflight.list(); len(rels) != 0 {
+		t.Errorf("Expected no in-flight releases, got %d", len(rels))
+	}
+}
+
+// blockingKubeClient blocks creating resources until release is closed.
+type blockingKubeClient struct {
+	environment.PrintingKubeClient
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingKubeClient) Create(ns string, r io.Reader) error {
+	close(b.started)
+	<-b.release
+	return b.PrintingKubeClient.Create(ns, r)
+}
+
+func TestInterruptReleasesDuringInstall(t *testing.T) {
+	rs := rsFixture()
+	kc := &blockingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		started:            make(chan struct{}),
+		release:            make(chan struct{}),
+	}
+	rs.env.KubeClient = kc
+
+	type result struct {
+		res *services.InstallReleaseResponse
+		err error
+	}
+	done := make(chan result)
+	go func() {
+		req := &services.InstallReleaseRequest{Name: "slow", Chart: chartStub(), Namespace: "spaced", DisableHooks: true}
+		res, err := rs.InstallRelease(helm.NewContext(), req)
+		done <- result{res, err}
+	}()
+
+	<-kc.started
+	rs.InterruptReleases()
+	close(kc.release)
+	r := <-done
+
+	if r.err != errInterrupted {
+		t.Errorf("Expected the install to report that it was interrupted, got %v", r.err)
+	}
+	got, err := rs.env.Releases.Get("slow", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Info.Status.Code != release.Status_INTERRUPTED {
+		t.Errorf("Expected release to stay INTERRUPTED, got %s", got.Info.Status.Code)
+	}
+	if r.res.Release == got {
+		t.Error("Expected the interrupted release to be a copy of the one being installed")
+	}
+}
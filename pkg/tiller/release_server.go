@@ -14,7 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package tiller
 
 import (
 	"bytes"
@@ -24,26 +24,31 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 
+	"github.com/Masterminds/semver"
 	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/proto"
 	"github.com/technosophos/moniker"
 	ctx "golang.org/x/net/context"
+	"k8s.io/kubernetes/pkg/api"
 	"k8s.io/kubernetes/pkg/api/unversioned"
 
-	"k8s.io/helm/cmd/tiller/environment"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/timeconv"
 	"k8s.io/helm/pkg/version"
 )
 
-var srv *releaseServer
-
 // releaseNameMaxLen is the maximum length of a release name.
 //
 // This is designed to accommodate the usage of release name in the 'name:'
@@ -57,13 +62,6 @@ const releaseNameMaxLen = 14
 // since there can be filepath in front of it.
 const notesFileSuffix = "NOTES.txt"
 
-func init() {
-	srv = &releaseServer{
-		env: env,
-	}
-	services.RegisterReleaseServiceServer(rootServer, srv)
-}
-
 var (
 	// errMissingChart indicates that a chart was not provided.
 	errMissingChart = errors.New("no chart provided")
@@ -76,20 +74,63 @@ var (
 // ListDefaultLimit is the default limit for number of items returned in a list.
 var ListDefaultLimit int64 = 512
 
-type releaseServer struct {
+// ListMaxBatchSize is the maximum size in bytes of the releases sent in a
+// single ListReleasesResponse message. It is kept well under gRPC's default
+// 4MB message size limit.
+var ListMaxBatchSize = 1024 * 1024
+
+// ReleaseServer manages releases in the Kubernetes cluster and storage of its
+// environment. It implements services.ReleaseServiceServer.
+type ReleaseServer struct {
 	env *environment.Environment
+	// inflight holds the releases being installed, upgraded, rolled back or
+	// deleted, so they can be recorded as interrupted on shutdown.
+	inflight inflightReleases
+
+	// HookObserver, if set, is called each time a hook finishes running, with
+	// the hook, the time it started and its error, if any.
+	HookObserver func(hook string, start time.Time, err error)
+}
+
+// NewReleaseServer creates a new release server for the environment.
+func NewReleaseServer(env *environment.Environment) *ReleaseServer {
+	return &ReleaseServer{
+		env: env,
+	}
 }
 
 func getVersion(c ctx.Context) string {
+	return getMetadata(c, "x-helm-api-client")
+}
+
+// getMetadata returns the first value of the gRPC metadata key, or "" if the
+// client did not send it.
+func getMetadata(c ctx.Context, key string) string {
 	if md, ok := metadata.FromContext(c); ok {
-		if v, ok := md["x-helm-api-client"]; ok {
+		if v, ok := md[key]; ok && len(v) > 0 {
 			return v[0]
 		}
 	}
 	return ""
 }
 
-func (s *releaseServer) ListReleases(req *services.ListReleasesRequest, stream services.ReleaseService_ListReleasesServer) error {
+// CallerIdentity returns the caller identity reported by the client.
+func CallerIdentity(c ctx.Context) *release.Identity {
+	return &release.Identity{
+		KubeUser:      getMetadata(c, "x-helm-kube-user"),
+		OsUser:        getMetadata(c, "x-helm-os-user"),
+		Hostname:      getMetadata(c, "x-helm-hostname"),
+		ClientVersion: getVersion(c),
+	}
+}
+
+// stampRelease records who performed an operation, and which, on a release.
+func stampRelease(c ctx.Context, rel *release.Release, operation string) {
+	rel.Info.Identity = CallerIdentity(c)
+	rel.Info.Operation = operation
+}
+
+func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream services.ReleaseService_ListReleasesServer) error {
 	if !checkClientVersion(stream.Context()) {
 		return errIncompatibleVersion
 	}
@@ -98,15 +139,18 @@ func (s *releaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		req.StatusCodes = []release.Status_Code{release.Status_DEPLOYED}
 	}
 
-	//rels, err := s.env.Releases.ListDeployed()
-	rels, err := s.env.Releases.ListFilterAll(func(r *release.Release) bool {
-		for _, sc := range req.StatusCodes {
-			if sc == r.Info.Status.Code {
-				return true
-			}
-		}
-		return false
-	})
+	filters, err := listFilters(req)
+	if err != nil {
+		return err
+	}
+
+	var rels []*release.Release
+	if len(req.Labels) > 0 {
+		// Labels are pushed down to the storage driver as a label query.
+		rels, err = s.env.Releases.QueryFilterAll(req.Labels, filters...)
+	} else {
+		rels, err = s.env.Releases.ListFilterAll(filters...)
+	}
 	if err != nil {
 		return err
 	}
@@ -168,15 +212,60 @@ func (s *releaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		l = int64(len(rels))
 	}
 
+	// Stream the results in batches so that large installations do not
+	// exceed the maximum message size.
+	batch := []*release.Release{}
+	size := 0
+	for _, r := range rels {
+		rs := proto.Size(r)
+		if len(batch) > 0 && size+rs > ListMaxBatchSize {
+			if err := sendReleases(stream, batch, next, l, total); err != nil {
+				return err
+			}
+			batch = []*release.Release{}
+			size = 0
+		}
+		batch = append(batch, r)
+		size += rs
+	}
+	return sendReleases(stream, batch, next, l, total)
+}
+
+func sendReleases(stream services.ReleaseService_ListReleasesServer, rels []*release.Release, next string, count, total int64) error {
 	res := &services.ListReleasesResponse{
 		Next:     next,
-		Count:    l,
+		Count:    count,
 		Total:    total,
 		Releases: rels,
 	}
 	return stream.Send(res)
 }
 
+// listFilters builds the storage filters for the status codes, namespace, and
+// chart constraints of a list request.
+func listFilters(req *services.ListReleasesRequest) ([]storage.FilterFunc, error) {
+	var statuses []storage.FilterFunc
+	for _, sc := range req.StatusCodes {
+		statuses = append(statuses, storage.StatusFilter(sc))
+	}
+	filters := []storage.FilterFunc{storage.Any(statuses...)}
+
+	if req.Namespace != "" {
+		filters = append(filters, storage.NamespaceFilter(req.Namespace))
+	}
+	if req.ChartName != "" {
+		filters = append(filters, storage.ChartNameFilter(req.ChartName))
+	}
+	if req.ChartVersion != "" {
+		c, err := semver.NewConstraint(req.ChartVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid chart version range %q: %s", req.ChartVersion, err)
+		}
+		filters = append(filters, storage.ChartVersionFilter(c))
+	}
+	return filters, nil
+}
+
 func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
 	preg, err := regexp.Compile(filter)
 	if err != nil {
@@ -191,7 +280,7 @@ func filterReleases(filter string, rels []*release.Release) ([]*release.Release,
 	return matches, nil
 }
 
-func (s *releaseServer) GetVersion(c ctx.Context, req *services.GetVersionRequest) (*services.GetVersionResponse, error) {
+func (s *ReleaseServer) GetVersion(c ctx.Context, req *services.GetVersionRequest) (*services.GetVersionResponse, error) {
 	v := version.GetVersionProto()
 	return &services.GetVersionResponse{Version: v}, nil
 }
@@ -201,7 +290,7 @@ func checkClientVersion(c ctx.Context) bool {
 	return version.IsCompatible(v, version.Version)
 }
 
-func (s *releaseServer) GetReleaseStatus(c ctx.Context, req *services.GetReleaseStatusRequest) (*services.GetReleaseStatusResponse, error) {
+func (s *ReleaseServer) GetReleaseStatus(c ctx.Context, req *services.GetReleaseStatusRequest) (*services.GetReleaseStatusResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -237,8 +326,8 @@ func (s *releaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 	// manifest we stashed away with reality from the cluster.
 	kubeCli := s.env.KubeClient
 	resp, err := kubeCli.Get(rel.Namespace, bytes.NewBufferString(rel.Manifest))
-	if sc == release.Status_DELETED || sc == release.Status_FAILED {
-		// Skip errors if this is already deleted or failed.
+	if sc == release.Status_DELETED || sc == release.Status_FAILED || sc == release.Status_INTERRUPTED {
+		// Skip errors if this is already deleted, failed or interrupted.
 		return statusResp, nil
 	} else if err != nil {
 		log.Printf("warning: Get for %s failed: %v", rel.Name, err)
@@ -248,7 +337,7 @@ func (s *releaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 	return statusResp, nil
 }
 
-func (s *releaseServer) GetReleaseContent(c ctx.Context, req *services.GetReleaseContentRequest) (*services.GetReleaseContentResponse, error) {
+func (s *ReleaseServer) GetReleaseContent(c ctx.Context, req *services.GetReleaseContentRequest) (*services.GetReleaseContentResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -265,7 +354,7 @@ func (s *releaseServer) GetReleaseContent(c ctx.Context, req *services.GetReleas
 	return &services.GetReleaseContentResponse{Release: rel}, err
 }
 
-func (s *releaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -274,6 +363,11 @@ func (s *releaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 	if err != nil {
 		return nil, err
 	}
+	stampRelease(c, updatedRelease, "UpdateRelease")
+	if !req.DryRun {
+		s.inflight.add(updatedRelease)
+		defer s.inflight.remove(updatedRelease)
+	}
 
 	res, err := s.performUpdate(currentRelease, updatedRelease, req)
 	if err != nil {
@@ -289,7 +383,7 @@ func (s *releaseServer) UpdateRelease(c ctx.Context, req *services.UpdateRelease
 	return res, nil
 }
 
-func (s *releaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	res := &services.UpdateReleaseResponse{Release: updatedRelease}
 
 	if req.DryRun {
@@ -315,6 +409,9 @@ func (s *releaseServer) performUpdate(originalRelease, updatedRelease *release.R
 		}
 	}
 
+	if !s.inflight.finish(updatedRelease) {
+		return nil, errInterrupted
+	}
 	originalRelease.Info.Status.Code = release.Status_SUPERSEDED
 	if err := s.env.Releases.Update(originalRelease); err != nil {
 		return nil, fmt.Errorf("Update of %s failed: %s", originalRelease.Name, err)
@@ -326,7 +423,7 @@ func (s *releaseServer) performUpdate(originalRelease, updatedRelease *release.R
 }
 
 // prepareUpdate builds an updated release for an update operation.
-func (s *releaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*release.Release, *release.Release, error) {
+func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*release.Release, *release.Release, error) {
 	if req.Name == "" {
 		return nil, nil, errMissingRelease
 	}
@@ -341,6 +438,15 @@ func (s *releaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		return nil, nil, err
 	}
 
+	if err := s.checkNamespace(currentRelease.Namespace); err != nil {
+		return nil, nil, err
+	}
+
+	caps, err := s.capabilities()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	ts := timeconv.Now()
 	options := chartutil.ReleaseOptions{
 		Name:      req.Name,
@@ -348,12 +454,21 @@ func (s *releaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 		Namespace: currentRelease.Namespace,
 	}
 
-	valuesToRender, err := chartutil.ToRenderValues(req.Chart, req.Values, options)
+	valuesToRender, err := chartutil.ToRenderValues(req.Chart, req.Values, options, caps)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender)
+	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.checkManifestNamespaces(options.Namespace, manifestDoc.String(), hooks); err != nil {
+		return nil, nil, err
+	}
+
+	labels, err := mergeLabels(currentRelease.Info.Labels, req.Labels)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -368,6 +483,8 @@ func (s *releaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 			FirstDeployed: currentRelease.Info.FirstDeployed,
 			LastDeployed:  ts,
 			Status:        &release.Status{Code: release.Status_UNKNOWN},
+			Description:   req.Description,
+			Labels:        labels,
 		},
 		Version:  currentRelease.Version + 1,
 		Manifest: manifestDoc.String(),
@@ -380,7 +497,7 @@ func (s *releaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*rele
 	return currentRelease, updatedRelease, nil
 }
 
-func (s *releaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -389,6 +506,11 @@ func (s *releaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 	if err != nil {
 		return nil, err
 	}
+	stampRelease(c, targetRelease, "RollbackRelease")
+	if !req.DryRun {
+		s.inflight.add(targetRelease)
+		defer s.inflight.remove(targetRelease)
+	}
 
 	rel, err := s.performRollback(currentRelease, targetRelease, req)
 	if err != nil {
@@ -404,7 +526,7 @@ func (s *releaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 	return rel, nil
 }
 
-func (s *releaseServer) performRollback(currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	res := &services.RollbackReleaseResponse{Release: targetRelease}
 
 	if req.DryRun {
@@ -430,6 +552,9 @@ func (s *releaseServer) performRollback(currentRelease, targetRelease *release.R
 		}
 	}
 
+	if !s.inflight.finish(targetRelease) {
+		return nil, errInterrupted
+	}
 	currentRelease.Info.Status.Code = release.Status_SUPERSEDED
 	if err := s.env.Releases.Update(currentRelease); err != nil {
 		return nil, fmt.Errorf("Update of %s failed: %s", currentRelease.Name, err)
@@ -440,7 +565,7 @@ func (s *releaseServer) performRollback(currentRelease, targetRelease *release.R
 	return res, nil
 }
 
-func (s *releaseServer) performKubeUpdate(currentRelease, targetRelease *release.Release) error {
+func (s *ReleaseServer) performKubeUpdate(currentRelease, targetRelease *release.Release) error {
 	kubeCli := s.env.KubeClient
 	current := bytes.NewBufferString(currentRelease.Manifest)
 	target := bytes.NewBufferString(targetRelease.Manifest)
@@ -449,7 +574,7 @@ func (s *releaseServer) performKubeUpdate(currentRelease, targetRelease *release
 
 // prepareRollback finds the previous release and prepares a new release object with
 //  the previous release's configuration
-func (s *releaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*release.Release, *release.Release, error) {
+func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*release.Release, *release.Release, error) {
 
 	if req.Name == "" {
 		return nil, nil, errMissingRelease
@@ -466,6 +591,18 @@ func (s *releaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 		return nil, nil, err
 	}
 
+	if err := s.checkNamespace(currentRelease.Namespace); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkManifestNamespaces(currentRelease.Namespace, previousRelease.Manifest, previousRelease.Hooks); err != nil {
+		return nil, nil, err
+	}
+
+	labels, err := mergeLabels(currentRelease.Info.Labels, req.Labels)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	ts := timeconv.Now()
 
 	// Store a new release object with previous release's configuration
@@ -481,6 +618,8 @@ func (s *releaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 				Code:  release.Status_UNKNOWN,
 				Notes: previousRelease.Info.Status.Notes,
 			},
+			Description: req.Description,
+			Labels:      labels,
 		},
 		Version:  currentRelease.Version + 1,
 		Manifest: previousRelease.Manifest,
@@ -490,7 +629,7 @@ func (s *releaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 	return currentRelease, targetRelease, nil
 }
 
-func (s *releaseServer) uniqName(start string, reuse bool) (string, error) {
+func (s *ReleaseServer) uniqName(start string, reuse bool) (string, error) {
 
 	// If a name is supplied, we check to see if that name is taken. If not, it
 	// is granted. If reuse is true and a deleted release with that name exists,
@@ -503,7 +642,7 @@ func (s *releaseServer) uniqName(start string, reuse bool) (string, error) {
 
 		if rel, err := s.env.Releases.Get(start, 1); err == driver.ErrReleaseNotFound {
 			return start, nil
-		} else if st := rel.Info.Status.Code; reuse && (st == release.Status_DELETED || st == release.Status_FAILED) {
+		} else if st := rel.Info.Status.Code; reuse && (st == release.Status_DELETED || st == release.Status_FAILED || st == release.Status_INTERRUPTED) {
 			// Allowe re-use of names if the previous release is marked deleted.
 			log.Printf("reusing name %q", start)
 			return start, nil
@@ -530,7 +669,7 @@ func (s *releaseServer) uniqName(start string, reuse bool) (string, error) {
 	return "ERROR", errors.New("no available release name found")
 }
 
-func (s *releaseServer) engine(ch *chart.Chart) environment.Engine {
+func (s *ReleaseServer) engine(ch *chart.Chart) environment.Engine {
 	renderer := s.env.EngineYard.Default()
 	if ch.Metadata.Engine != "" {
 		if r, ok := s.env.EngineYard.Get(ch.Metadata.Engine); ok {
@@ -542,7 +681,7 @@ func (s *releaseServer) engine(ch *chart.Chart) environment.Engine {
 	return renderer
 }
 
-func (s *releaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -552,6 +691,11 @@ func (s *releaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 		log.Printf("Failed install prepare step: %s", err)
 		return nil, err
 	}
+	stampRelease(c, rel, "InstallRelease")
+	if !req.DryRun {
+		s.inflight.add(rel)
+		defer s.inflight.remove(rel)
+	}
 
 	res, err := s.performRelease(rel, req)
 	if err != nil {
@@ -561,24 +705,42 @@ func (s *releaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 }
 
 // prepareRelease builds a release for an install operation.
-func (s *releaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, error) {
+func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, error) {
 	if req.Chart == nil {
 		return nil, errMissingChart
 	}
 
+	if err := s.checkNamespace(req.Namespace); err != nil {
+		return nil, err
+	}
+
 	name, err := s.uniqName(req.Name, req.ReuseName)
 	if err != nil {
 		return nil, err
 	}
 
+	caps, err := s.capabilities()
+	if err != nil {
+		return nil, err
+	}
+
 	ts := timeconv.Now()
 	options := chartutil.ReleaseOptions{Name: name, Time: ts, Namespace: req.Namespace}
-	valuesToRender, err := chartutil.ToRenderValues(req.Chart, req.Values, options)
+	valuesToRender, err := chartutil.ToRenderValues(req.Chart, req.Values, options, caps)
 	if err != nil {
 		return nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender)
+	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkManifestNamespaces(options.Namespace, manifestDoc.String(), hooks); err != nil {
+		return nil, err
+	}
+
+	labels, err := mergeLabels(nil, req.Labels)
 	if err != nil {
 		return nil, err
 	}
@@ -593,6 +755,8 @@ func (s *releaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 			FirstDeployed: ts,
 			LastDeployed:  ts,
 			Status:        &release.Status{Code: release.Status_UNKNOWN},
+			Description:   req.Description,
+			Labels:        labels,
 		},
 		Manifest: manifestDoc.String(),
 		Hooks:    hooks,
@@ -604,8 +768,8 @@ func (s *releaseServer) prepareRelease(req *services.InstallReleaseRequest) (*re
 	return rel, nil
 }
 
-func (s *releaseServer) getVersionSet() (versionSet, error) {
-	defVersions := newVersionSet("v1")
+func (s *ReleaseServer) getVersionSet() (chartutil.VersionSet, error) {
+	defVersions := chartutil.DefaultVersionSet
 	cli, err := s.env.KubeClient.APIClient()
 	if err != nil {
 		log.Printf("API Client for Kubernetes is missing: %s.", err)
@@ -626,40 +790,35 @@ func (s *releaseServer) getVersionSet() (versionSet, error) {
 	}
 
 	versions := unversioned.ExtractGroupVersions(groups)
-	return newVersionSet(versions...), nil
+	return chartutil.NewVersionSet(versions...), nil
 }
 
-func (s *releaseServer) renderResources(ch *chart.Chart, values chartutil.Values) ([]*release.Hook, *bytes.Buffer, string, error) {
-	renderer := s.engine(ch)
-	files, err := renderer.Render(ch, values)
+// capabilities returns the capabilities of the cluster and of Tiller, which
+// are available to templates as .Capabilities.
+func (s *ReleaseServer) capabilities() (*chartutil.Capabilities, error) {
+	vs, err := s.getVersionSet()
 	if err != nil {
-		return nil, nil, "", err
+		return nil, fmt.Errorf("Could not get apiVersions from Kubernetes: %s", err)
 	}
-
-	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
-	// pull it out of here into a separate file so that we can actually use the output of the rendered
-	// text file. We have to spin through this map because the file contains path information, so we
-	// look for terminating NOTES.txt. We also remove it from the files so that we don't have to skip
-	// it in the sortHooks.
-	notes := ""
-	for k, v := range files {
-		if strings.HasSuffix(k, notesFileSuffix) {
-			notes = v
-			delete(files, k)
-		}
+	cli, err := s.env.KubeClient.APIClient()
+	if err != nil {
+		return nil, fmt.Errorf("Could not create Kubernetes client: %s", err)
 	}
-
-	// Sort hooks, manifests, and partials. Only hooks and manifests are returned,
-	// as partials are not used after renderer.Render. Empty manifests are also
-	// removed here.
-	vs, err := s.getVersionSet()
+	sv, err := cli.Discovery().ServerVersion()
 	if err != nil {
-		return nil, nil, "", fmt.Errorf("Could not get apiVersions from Kubernetes: %s", err)
+		return nil, fmt.Errorf("Could not get server version from Kubernetes: %s", err)
 	}
-	hooks, manifests, err := sortManifests(files, vs, InstallOrder)
+	return &chartutil.Capabilities{
+		APIVersions:   vs,
+		KubeVersion:   sv,
+		TillerVersion: version.GetVersionProto(),
+	}, nil
+}
+
+// renderResources renders ch, rejecting templates whose apiVersion is not in vs.
+func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet) ([]*release.Hook, *bytes.Buffer, string, error) {
+	hooks, manifests, notes, err := render(s.engine(ch), ch, values, vs)
 	if err != nil {
-		// By catching parse errors here, we can prevent bogus releases from going
-		// to Kubernetes.
 		return nil, nil, "", err
 	}
 
@@ -673,13 +832,83 @@ func (s *releaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 	return hooks, b, notes, nil
 }
 
+// mergeLabels validates the user-supplied labels and applies them on top of
+// the labels of an existing release.
+func mergeLabels(current, labels map[string]string) (map[string]string, error) {
+	if err := driver.ValidateLabels(labels); err != nil {
+		return nil, err
+	}
+	if len(current) == 0 && len(labels) == 0 {
+		return nil, nil
+	}
+	merged := map[string]string{}
+	for k, v := range current {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged, nil
+}
+
+// checkNamespace returns a permission error if tiller may not manage
+// resources in namespace.
+func (s *ReleaseServer) checkNamespace(namespace string) error {
+	if namespace == "" {
+		// Resources without a namespace end up in the default namespace.
+		namespace = api.NamespaceDefault
+	}
+	if !s.env.NamespaceAllowed(namespace) {
+		return grpc.Errorf(codes.PermissionDenied, "namespace %q is not allowed", namespace)
+	}
+	return nil
+}
+
+// checkManifestNamespaces returns a permission error if any of the resources
+// in the manifest or hooks would be created in a namespace tiller may not
+// manage. The resources are decoded the way the KubeClient creates them, so
+// the items of lists are checked too, and a manifest that cannot be decoded is
+// rejected.
+func (s *ReleaseServer) checkManifestNamespaces(namespace, manifest string, hooks []*release.Hook) error {
+	if len(s.env.AllowedNamespaces) == 0 {
+		return nil
+	}
+
+	manifests := []string{manifest}
+	for _, h := range hooks {
+		manifests = append(manifests, h.Manifest)
+	}
+
+	for _, m := range manifests {
+		if strings.TrimSpace(m) == "" {
+			continue
+		}
+		namespaces, err := s.env.KubeClient.Namespaces(namespace, bytes.NewBufferString(m))
+		if err != nil {
+			return fmt.Errorf("cannot check the namespaces of the manifest: %s", err)
+		}
+		for _, ns := range namespaces {
+			if err := s.checkNamespace(ns); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // validateYAML checks to see if YAML is well-formed.
 func validateYAML(data string) error {
 	b := map[string]interface{}{}
 	return yaml.Unmarshal([]byte(data), b)
 }
 
-func (s *releaseServer) recordRelease(r *release.Release, reuse bool) {
+// recordRelease stores r with the status its install reached. It returns
+// errInterrupted, and leaves r unrecorded, if r was recorded as interrupted.
+func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) error {
+	if !s.inflight.finish(r) {
+		log.Printf("warning: Release %q completed after it was interrupted", r.Name)
+		return errInterrupted
+	}
 	if reuse {
 		if err := s.env.Releases.Update(r); err != nil {
 			log.Printf("warning: Failed to update release %q: %s", r.Name, err)
@@ -687,10 +916,11 @@ func (s *releaseServer) recordRelease(r *release.Release, reuse bool) {
 	} else if err := s.env.Releases.Create(r); err != nil {
 		log.Printf("warning: Failed to record release %q: %s", r.Name, err)
 	}
+	return nil
 }
 
 // performRelease runs a release.
-func (s *releaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
 	res := &services.InstallReleaseResponse{Release: r}
 
 	if req.DryRun {
@@ -733,11 +963,13 @@ func (s *releaseServer) performRelease(r *release.Release, req *services.Install
 	// One possible strategy would be to do a timed retry to see if we can get
 	// this stored in the future.
 	r.Info.Status.Code = release.Status_DEPLOYED
-	s.recordRelease(r, req.ReuseName)
+	if err := s.recordRelease(r, req.ReuseName); err != nil {
+		return res, err
+	}
 	return res, nil
 }
 
-func (s *releaseServer) execHook(hs []*release.Hook, name, namespace, hook string) error {
+func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook string) error {
 	kubeCli := s.env.KubeClient
 	code, ok := events[hook]
 	if !ok {
@@ -757,9 +989,11 @@ func (s *releaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 			continue
 		}
 
+		start := time.Now()
 		b := bytes.NewBufferString(h.Manifest)
 		if err := kubeCli.Create(namespace, b); err != nil {
 			log.Printf("warning: Release %q pre-install %s failed: %s", name, h.Path, err)
+			s.observeHook(hook, start, err)
 			return err
 		}
 		// No way to rewind a bytes.Buffer()?
@@ -767,15 +1001,23 @@ func (s *releaseServer) execHook(hs []*release.Hook, name, namespace, hook strin
 		b.WriteString(h.Manifest)
 		if err := kubeCli.WatchUntilReady(namespace, b); err != nil {
 			log.Printf("warning: Release %q pre-install %s could not complete: %s", name, h.Path, err)
+			s.observeHook(hook, start, err)
 			return err
 		}
+		s.observeHook(hook, start, nil)
 		h.LastRun = timeconv.Now()
 	}
 	log.Printf("Hooks complete for %s %s", hook, name)
 	return nil
 }
 
-func (s *releaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
+func (s *ReleaseServer) observeHook(hook string, start time.Time, err error) {
+	if s.HookObserver != nil {
+		s.HookObserver(hook, start, err)
+	}
+}
+
+func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
 	if !checkClientVersion(c) {
 		return nil, errIncompatibleVersion
 	}
@@ -791,6 +1033,10 @@ func (s *releaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		return nil, err
 	}
 
+	if err := s.checkNamespace(rel.Namespace); err != nil {
+		return nil, err
+	}
+
 	// TODO: Are there any cases where we want to force a delete even if it's
 	// already marked deleted?
 	if rel.Info.Status.Code == release.Status_DELETED {
@@ -804,9 +1050,21 @@ func (s *releaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		return nil, fmt.Errorf("the release named %q is already deleted", req.Name)
 	}
 
+	labels, err := mergeLabels(rel.Info.Labels, req.Labels)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Printf("uninstall: Deleting %s", req.Name)
 	rel.Info.Status.Code = release.Status_DELETED
 	rel.Info.Deleted = timeconv.Now()
+	rel.Info.Labels = labels
+	if req.Description != "" {
+		rel.Info.Description = req.Description
+	}
+	stampRelease(c, rel, "UninstallRelease")
+	s.inflight.add(rel)
+	defer s.inflight.remove(rel)
 	res := &services.UninstallReleaseResponse{Release: rel}
 
 	if !req.DisableHooks {
@@ -843,6 +1101,9 @@ func (s *releaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		}
 	}
 
+	if !s.inflight.finish(rel) {
+		return nil, errInterrupted
+	}
 	if !req.Purge {
 		if err := s.env.Releases.Update(rel); err != nil {
 			log.Printf("uninstall: Failed to store updated release: %s", err)
@@ -14,28 +14,39 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
-package main
+package tiller
 
 import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"k8s.io/kubernetes/pkg/api/meta"
+	"k8s.io/kubernetes/pkg/client/unversioned/fake"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+	kversion "k8s.io/kubernetes/pkg/version"
 
-	"k8s.io/helm/cmd/tiller/environment"
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/version"
 )
 
 const notesText = "my notes here"
@@ -70,8 +81,8 @@ data:
   name: value
 `
 
-func rsFixture() *releaseServer {
-	return &releaseServer{
+func rsFixture() *ReleaseServer {
+	return &ReleaseServer{
 		env: mockEnvironment(),
 	}
 }
@@ -393,6 +404,103 @@ func TestInstallReleaseWithNotesRendered(t *testing.T) {
 	}
 }
 
+func TestInstallReleaseCapabilities(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "caps", Data: []byte(`kube: "{{.Capabilities.KubeVersion.Major}}"
+tiller: "{{.Capabilities.TillerVersion.SemVer}}"
+v1: {{.Capabilities.APIVersions.Has "v1"}}`)},
+			},
+		},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	expect := fmt.Sprintf("kube: %q\ntiller: %q\nv1: true", kversion.Get().Major, version.GetVersion())
+	if !strings.Contains(res.Release.Manifest, expect) {
+		t.Errorf("Expected manifest to contain %q, got %q", expect, res.Release.Manifest)
+	}
+}
+
+func TestInstallReleaseRenderLimits(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.EngineYard[environment.GoTplEngine].(*engine.Engine).MaxIncludeDepth = 5
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "loop", Data: []byte(`{{define "loop"}}{{include "loop" .}}{{end}}{{include "loop" .}}`)},
+			},
+		},
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install to fail")
+	}
+	if !strings.Contains(err.Error(), "include depth exceeds the limit of 5") {
+		t.Errorf("Expected an include depth error, got %q", err)
+	}
+	if rels, _ := rs.env.Releases.ListReleases(); len(rels) != 0 {
+		t.Errorf("Expected no release to be stored, got %d", len(rels))
+	}
+}
+
+func TestInstallReleaseSchema(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+			Values:    &chart.Config{Raw: "replicaCount: 1"},
+			Schema:    []byte(`{"properties": {"replicaCount": {"type": "integer"}}}`),
+		},
+		Values: &chart.Config{Raw: "replicaCount: one"},
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected install to fail")
+	}
+	if !strings.Contains(err.Error(), "$.replicaCount: expected integer, got string") {
+		t.Errorf("Expected a schema error, got %q", err)
+	}
+}
+
+func TestInstallReleaseRawEngine(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello", Engine: environment.RawEngine},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte(`hello: "{{not a template}}"`)},
+			},
+		},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, `hello: "{{not a template}}"`) {
+		t.Errorf("Expected the template unmodified, got %q", res.Release.Manifest)
+	}
+}
+
 func TestInstallReleaseDryRun(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -628,6 +736,203 @@ func TestUpdateReleaseNoChanges(t *testing.T) {
 	}
 }
 
+func TestInstallReleaseIdentity(t *testing.T) {
+	c := metadata.NewContext(helm.NewContext(), metadata.Pairs(
+		"x-helm-api-client", version.Version,
+		"x-helm-kube-user", "alice",
+		"x-helm-os-user", "al",
+		"x-helm-hostname", "laptop",
+	))
+	rs := rsFixture()
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Chart: chartStub()})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	rel, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
+	if err != nil {
+		t.Fatalf("Expected release for %s (%v).", res.Release.Name, rs.env.Releases)
+	}
+	expect := &release.Identity{
+		KubeUser:      "alice",
+		OsUser:        "al",
+		Hostname:      "laptop",
+		ClientVersion: version.Version,
+	}
+	if !reflect.DeepEqual(rel.Info.Identity, expect) {
+		t.Errorf("Expected identity %v, got %v", expect, rel.Info.Identity)
+	}
+	if rel.Info.Operation != "InstallRelease" {
+		t.Errorf("Expected operation InstallRelease, got %q", rel.Info.Operation)
+	}
+}
+
+func TestInstallReleaseNamespaceNotAllowed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.AllowedNamespaces = []string{"team-a"}
+
+	req := &services.InstallReleaseRequest{Namespace: "kube-system", Chart: chartStub()}
+	_, err := rs.InstallRelease(c, req)
+	if grpc.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected a permission error, got %v", err)
+	}
+
+	req.Namespace = "team-a"
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+}
+
+func TestInstallReleaseManifestNamespaceNotAllowed(t *testing.T) {
+	const configMap = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n"
+	tests := []struct {
+		name     string
+		manifest string
+		code     codes.Code
+	}{
+		{
+			name:     "default namespace",
+			manifest: fmt.Sprintf(configMap, "stay"),
+			code:     codes.OK,
+		},
+		{
+			name:     "explicit namespace",
+			manifest: fmt.Sprintf(configMap, "escape") + "  namespace: kube-system\n",
+			code:     codes.PermissionDenied,
+		},
+		{
+			name:     "separator with trailing space",
+			manifest: fmt.Sprintf(configMap, "stay") + "--- \n" + fmt.Sprintf(configMap, "escape") + "  namespace: kube-system\n",
+			code:     codes.PermissionDenied,
+		},
+		{
+			name: "list item",
+			manifest: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: escape
+    namespace: kube-system
+`,
+			code: codes.PermissionDenied,
+		},
+		{
+			name:     "not a resource",
+			manifest: "namespace: kube-system\n",
+			code:     codes.Unknown,
+		},
+	}
+
+	for _, tt := range tests {
+		c := helm.NewContext()
+		rs := rsFixture()
+		rs.env.KubeClient = newDecodingKubeClient()
+		rs.env.AllowedNamespaces = []string{"team-a"}
+
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/manifest", Data: []byte(tt.manifest)}},
+		}
+		req := &services.InstallReleaseRequest{Namespace: "team-a", Chart: ch}
+		_, err := rs.InstallRelease(c, req)
+		if grpc.Code(err) != tt.code {
+			t.Errorf("%s: expected code %s, got %v", tt.name, tt.code, err)
+		}
+	}
+}
+
+func TestUninstallReleaseNamespaceNotAllowed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.AllowedNamespaces = []string{"team-a"}
+	rel := releaseStub()
+	rel.Namespace = "team-b"
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: rel.Name})
+	if grpc.Code(err) != codes.PermissionDenied {
+		t.Fatalf("Expected a permission error, got %v", err)
+	}
+}
+
+func TestInstallReleaseLabels(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Chart:       chartStub(),
+		Description: "initial rollout",
+		Labels:      map[string]string{"team": "payments"},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	rel, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
+	if err != nil {
+		t.Fatalf("Expected release for %s (%v).", res.Release.Name, rs.env.Releases)
+	}
+	if rel.Info.Description != "initial rollout" {
+		t.Errorf("Expected description %q, got %q", "initial rollout", rel.Info.Description)
+	}
+	if rel.Info.Labels["team"] != "payments" {
+		t.Errorf("Expected label team=payments, got %v", rel.Info.Labels)
+	}
+
+	rels, err := rs.env.Releases.QueryFilterAll(map[string]string{"team": "payments"})
+	if err != nil {
+		t.Fatalf("Failed query: %s", err)
+	}
+	if len(rels) != 1 {
+		t.Errorf("Expected 1 release labelled team=payments, got %d", len(rels))
+	}
+}
+
+func TestInstallReleaseReservedLabel(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Chart:  chartStub(),
+		Labels: map[string]string{"OWNER": "me"},
+	}
+	if _, err := rs.InstallRelease(c, req); err == nil {
+		t.Fatal("Expected an error for a reserved label")
+	}
+}
+
+func TestUpdateReleaseLabels(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Labels = map[string]string{"team": "payments", "env": "staging"}
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:        rel.Name,
+		Chart:       rel.Chart,
+		Description: "promote to prod",
+		Labels:      map[string]string{"env": "prod"},
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	expect := map[string]string{"team": "payments", "env": "prod"}
+	if !reflect.DeepEqual(res.Release.Info.Labels, expect) {
+		t.Errorf("Expected labels %v, got %v", expect, res.Release.Info.Labels)
+	}
+	if res.Release.Info.Description != "promote to prod" {
+		t.Errorf("Expected description %q, got %q", "promote to prod", res.Release.Info.Description)
+	}
+}
+
 func TestRollbackReleaseNoHooks(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -957,6 +1262,58 @@ func TestListReleases(t *testing.T) {
 	}
 }
 
+func TestListReleasesBatches(t *testing.T) {
+	rs := rsFixture()
+	num := 7
+	for i := 0; i < num; i++ {
+		rel := releaseStub()
+		rel.Name = fmt.Sprintf("rel-%d", i)
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	defer func(size int) { ListMaxBatchSize = size }(ListMaxBatchSize)
+	// Small enough that every release is sent in its own message.
+	ListMaxBatchSize = 1
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{Limit: 5, SortBy: services.ListSort_NAME}
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+
+	if mrs.batches != 5 {
+		t.Errorf("Expected 5 batches, got %d", mrs.batches)
+	}
+	if len(mrs.val.Releases) != 5 {
+		t.Errorf("Expected 5 releases, got %d", len(mrs.val.Releases))
+	}
+	if mrs.val.Count != 5 {
+		t.Errorf("Expected count 5, got %d", mrs.val.Count)
+	}
+	if mrs.val.Total != int64(num) {
+		t.Errorf("Expected total %d, got %d", num, mrs.val.Total)
+	}
+	if mrs.val.Next != "rel-5" {
+		t.Errorf("Expected next %q, got %q", "rel-5", mrs.val.Next)
+	}
+}
+
+func TestListReleasesEmpty(t *testing.T) {
+	rs := rsFixture()
+	mrs := &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if mrs.batches != 1 {
+		t.Errorf("Expected 1 batch, got %d", mrs.batches)
+	}
+	if len(mrs.val.Releases) != 0 {
+		t.Errorf("Expected no releases, got %d", len(mrs.val.Releases))
+	}
+}
+
 func TestListReleasesByStatus(t *testing.T) {
 	rs := rsFixture()
 	stubs := []*release.Release{
@@ -1102,6 +1459,77 @@ func TestListReleasesFilter(t *testing.T) {
 	}
 }
 
+func TestListReleasesNamespaceAndChart(t *testing.T) {
+	rs := rsFixture()
+	stubs := []struct {
+		name, namespace, chart, version string
+	}{
+		{"axon", "payments", "nginx", "1.2.0"},
+		{"dendrite", "payments", "nginx", "2.0.0"},
+		{"neuron", "payments", "redis", "1.2.0"},
+		{"synapse", "search", "nginx", "1.3.1"},
+	}
+	for _, stub := range stubs {
+		rel := namedReleaseStub(stub.name, release.Status_DEPLOYED)
+		rel.Namespace = stub.namespace
+		rel.Chart.Metadata.Name = stub.chart
+		rel.Chart.Metadata.Version = stub.version
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	tests := []struct {
+		req   *services.ListReleasesRequest
+		names []string
+	}{
+		{
+			req:   &services.ListReleasesRequest{Namespace: "payments"},
+			names: []string{"axon", "dendrite", "neuron"},
+		},
+		{
+			req:   &services.ListReleasesRequest{ChartName: "nginx"},
+			names: []string{"axon", "dendrite", "synapse"},
+		},
+		{
+			req:   &services.ListReleasesRequest{ChartName: "nginx", ChartVersion: "^1.0.0"},
+			names: []string{"axon", "synapse"},
+		},
+		{
+			req:   &services.ListReleasesRequest{Namespace: "payments", ChartVersion: "~1.2.0"},
+			names: []string{"axon", "neuron"},
+		},
+		{
+			req:   &services.ListReleasesRequest{Labels: map[string]string{"NAME": "neuron"}},
+			names: []string{"neuron"},
+		},
+		{
+			req:   &services.ListReleasesRequest{Namespace: "search", Labels: map[string]string{"NAME": "neuron"}},
+			names: []string{},
+		},
+	}
+
+	for i, tt := range tests {
+		tt.req.SortBy = services.ListSort_NAME
+		mrs := &mockListServer{}
+		if err := rs.ListReleases(tt.req, mrs); err != nil {
+			t.Fatalf("%d: Failed listing: %s", i, err)
+		}
+		var names []string
+		for _, rel := range mrs.val.Releases {
+			names = append(names, rel.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(tt.names, ",") {
+			t.Errorf("%d: Expected %v, got %v", i, tt.names, names)
+		}
+	}
+
+	req := &services.ListReleasesRequest{ChartVersion: "not a range"}
+	if err := rs.ListReleases(req, &mockListServer{}); err == nil {
+		t.Error("Expected an error for an invalid chart version range")
+	}
+}
+
 func mockEnvironment() *environment.Environment {
 	e := environment.New()
 	e.Releases = storage.Init(driver.NewMemory())
@@ -1123,12 +1551,40 @@ func (h *hookFailingKubeClient) WatchUntilReady(ns string, r io.Reader) error {
 	return errors.New("Failed watch")
 }
 
+// decodingKubeClient is a PrintingKubeClient that decodes resources like the
+// real client, so that their namespaces can be checked.
+type decodingKubeClient struct {
+	environment.PrintingKubeClient
+	kube *kube.Client
+}
+
+func newDecodingKubeClient() *decodingKubeClient {
+	c := kube.New(nil)
+	c.ClientForMapping = func(*meta.RESTMapping) (resource.RESTClient, error) {
+		return &fake.RESTClient{}, nil
+	}
+	return &decodingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		kube:               c,
+	}
+}
+
+func (d *decodingKubeClient) Namespaces(ns string, r io.Reader) ([]string, error) {
+	return d.kube.Namespaces(ns, r)
+}
+
 type mockListServer struct {
-	val *services.ListReleasesResponse
+	val     *services.ListReleasesResponse
+	batches int
 }
 
 func (l *mockListServer) Send(res *services.ListReleasesResponse) error {
-	l.val = res
+	l.batches++
+	if l.val == nil {
+		l.val = res
+		return nil
+	}
+	l.val.Releases = append(l.val.Releases, res.Releases...)
 	return nil
 }
 
@@ -0,0 +1,86 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// Manifest is a rendered template of a chart.
+type Manifest struct {
+	// Name is the path of the template, prefixed with the name of the chart.
+	Name string
+	// Content is the rendered template.
+	Content string
+}
+
+// Render renders the templates of ch with values, as Tiller does when it
+// installs a release, but without contacting Kubernetes.
+//
+// The rendered NOTES.txt is returned separately, and partials and empty
+// templates are dropped. Templates that declare a hook are returned as hooks,
+// and the others as manifests. If vs is not nil, templates with an apiVersion
+// it does not contain are rejected.
+func Render(renderer environment.Engine, ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet) ([]*release.Hook, []Manifest, string, error) {
+	hooks, manifests, notes, err := render(renderer, ch, values, vs)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	res := make([]Manifest, len(manifests))
+	for i, m := range manifests {
+		res[i] = Manifest{Name: m.name, Content: m.content}
+	}
+	return hooks, res, notes, nil
+}
+
+// render renders ch and sorts the results into hooks and manifests, which are
+// returned along with the rendered NOTES.txt.
+func render(renderer environment.Engine, ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet) ([]*release.Hook, []manifest, string, error) {
+	files, err := renderer.Render(ch, values)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
+	// pull it out of here into a separate file so that we can actually use the output of the rendered
+	// text file. We have to spin through this map because the file contains path information, so we
+	// look for terminating NOTES.txt. We also remove it from the files so that we don't have to skip
+	// it in the sortHooks.
+	notes := ""
+	for k, v := range files {
+		if strings.HasSuffix(k, notesFileSuffix) {
+			notes = v
+			delete(files, k)
+		}
+	}
+
+	// Sort hooks, manifests, and partials. Only hooks and manifests are returned,
+	// as partials are not used after renderer.Render. Empty manifests are also
+	// removed here.
+	hooks, manifests, err := sortManifests(files, vs, InstallOrder)
+	if err != nil {
+		// By catching parse errors here, we can prevent bogus releases from going
+		// to Kubernetes.
+		return nil, nil, "", err
+	}
+	return hooks, manifests, notes, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestRender(t *testing.T) {
+	ch := chartStub()
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/NOTES.txt", Data: []byte("{{.Release.Name}} is ready")})
+	vals, err := chartutil.ToRenderValues(ch, &chart.Config{}, chartutil.ReleaseOptions{Name: "rendered"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hooks, manifests, notes, err := Render(engine.New(), ch, vals, nil)
+	if err != nil {
+		t.Fatalf("Failed render: %s", err)
+	}
+	if notes != "rendered is ready" {
+		t.Errorf("Expected notes to be rendered, got %q", notes)
+	}
+	if len(hooks) != 1 || hooks[0].Path != "hello/hooks" {
+		t.Errorf("Expected the hooks template as a hook, got %v", hooks)
+	}
+	expect := []string{"hello/goodbye", "hello/hello", "hello/with-partials"}
+	if len(manifests) != len(expect) {
+		t.Fatalf("Expected manifests %v, got %v", expect, manifests)
+	}
+	for i, m := range manifests {
+		if m.Name != expect[i] {
+			t.Errorf("Expected manifest %d to be %s, got %s", i, expect[i], m.Name)
+		}
+	}
+
+	if _, _, _, err := Render(engine.New(), ch, vals, chartutil.NewVersionSet("extensions/v1beta1")); err == nil {
+		t.Error("Expected an error for an unavailable apiVersion")
+	}
+}
@@ -0,0 +1,24 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package tlsutil contains utilities for building the TLS configurations used
+between the Helm client and Tiller.
+
+Both sides load a certificate and key pair from PEM files. To enable mutual
+TLS, each side also loads a CA certificate and uses it to verify the
+certificate presented by the other side.
+*/
+package tlsutil // import "k8s.io/helm/pkg/tlsutil"
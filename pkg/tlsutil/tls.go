@@ -0,0 +1,105 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// Options represents configurable options used to create client and server TLS configurations.
+type Options struct {
+	// CaCertFile is the path to a PEM encoded CA certificate used to verify
+	// the peer. It is required when verification is enabled.
+	CaCertFile string
+	// CertFile is the path to a PEM encoded certificate.
+	CertFile string
+	// KeyFile is the path to the PEM encoded private key of CertFile.
+	KeyFile string
+	// Verify enables verification of the peer's certificate. On a server this
+	// requires clients to present a certificate signed by the CA.
+	Verify bool
+	// ServerName is used by clients to verify the hostname of the server.
+	ServerName string
+}
+
+// ClientConfig returns a TLS configuration for use by a Helm client.
+//
+// The client certificate is optional. If it is set, it is presented to
+// Tiller, which enables mutual TLS.
+func ClientConfig(opts Options) (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: !opts.Verify,
+		ServerName:         opts.ServerName,
+	}
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("could not load x509 key pair (cert: %q, key: %q): %s", opts.CertFile, opts.KeyFile, err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if opts.Verify {
+		pool, err := certPool(opts.CaCertFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// ServerConfig returns a TLS configuration for use by Tiller.
+//
+// If Verify is set, clients must present a certificate signed by the CA.
+func ServerConfig(opts Options) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not load x509 key pair (cert: %q, key: %q): %s", opts.CertFile, opts.KeyFile, err)
+	}
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.NoClientCert,
+	}
+	if opts.Verify {
+		pool, err := certPool(opts.CaCertFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return cfg, nil
+}
+
+// certPool returns a pool holding the PEM encoded certificates in caFile.
+func certPool(caFile string) (*x509.CertPool, error) {
+	if caFile == "" {
+		return nil, fmt.Errorf("a CA certificate is required to verify peers")
+	}
+	b, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read CA certificate %q: %s", caFile, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("failed to append certificates from file: %s", caFile)
+	}
+	return pool, nil
+}
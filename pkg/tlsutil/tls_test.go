@@ -0,0 +1,205 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tlsutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testPKI holds the files of a CA and a server and client certificate signed by it.
+type testPKI struct {
+	dir        string
+	ca         string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	dir, err := ioutil.TempDir("", "helm-tlsutil-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &testPKI{dir: dir}
+
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "helm-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.ca = p.writePEM(t, "ca.crt", "CERTIFICATE", caDER)
+
+	issue := func(serial int64, name string, usage x509.ExtKeyUsage) (string, string) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			DNSNames:     []string{name},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert := p.writePEM(t, name+".crt", "CERTIFICATE", der)
+		keyFile := p.writePEM(t, name+".key", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+		return cert, keyFile
+	}
+	p.serverCert, p.serverKey = issue(2, "tiller", x509.ExtKeyUsageServerAuth)
+	p.clientCert, p.clientKey = issue(3, "helm", x509.ExtKeyUsageClientAuth)
+	return p
+}
+
+func (p *testPKI) writePEM(t *testing.T, name, typ string, b []byte) string {
+	path := filepath.Join(p.dir, name)
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// handshake performs a TLS handshake between the client and server configurations.
+func handshake(t *testing.T, client, server *tls.Config) error {
+	lstn, err := tls.Listen("tcp", "127.0.0.1:0", server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lstn.Close()
+
+	srvErr := make(chan error, 1)
+	go func() {
+		conn, err := lstn.Accept()
+		if err != nil {
+			srvErr <- err
+			return
+		}
+		defer conn.Close()
+		srvErr <- conn.(*tls.Conn).Handshake()
+	}()
+
+	conn, err := tls.Dial("tcp", lstn.Addr().String(), client)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return <-srvErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	server, err := ServerConfig(Options{CertFile: p.serverCert, KeyFile: p.serverKey, CaCertFile: p.ca, Verify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := ClientConfig(Options{CertFile: p.clientCert, KeyFile: p.clientKey, CaCertFile: p.ca, Verify: true, ServerName: "tiller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, client, server); err != nil {
+		t.Errorf("expected handshake to succeed, got %s", err)
+	}
+
+	anonymous, err := ClientConfig(Options{CaCertFile: p.ca, Verify: true, ServerName: "tiller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, anonymous, server); err == nil {
+		t.Error("expected handshake without a client certificate to fail")
+	}
+}
+
+func TestServerTLSWithoutVerify(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	server, err := ServerConfig(Options{CertFile: p.serverCert, KeyFile: p.serverKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := ClientConfig(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, client, server); err != nil {
+		t.Errorf("expected handshake to succeed, got %s", err)
+	}
+}
+
+func TestClientConfigWrongServerName(t *testing.T) {
+	p := newTestPKI(t)
+	defer os.RemoveAll(p.dir)
+
+	server, err := ServerConfig(Options{CertFile: p.serverCert, KeyFile: p.serverKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := ClientConfig(Options{CaCertFile: p.ca, Verify: true, ServerName: "not-tiller"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(t, client, server); err == nil {
+		t.Error("expected handshake with the wrong server name to fail")
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	if _, err := ServerConfig(Options{CertFile: "missing.crt", KeyFile: "missing.key"}); err == nil {
+		t.Error("expected an error for a missing key pair")
+	}
+	if _, err := ClientConfig(Options{Verify: true}); err == nil {
+		t.Error("expected an error when verifying without a CA certificate")
+	}
+}